@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BatchElem is a single call of a JSON-RPC batch request.
+// Result and Error are filled in per element once the batch has been sent
+type BatchElem struct {
+	Method types.RPCMethod
+	Params []any
+	Result json.RawMessage
+	Error  error
+}
+
+// rpcBatchCall sends the batch over a go-ethereum rpc.Client.
+// The returned error is only set for transport failures, per element errors are kept in the elements
+func rpcBatchCall(ctx context.Context, c *rpc.Client, batch []BatchElem) error {
+	elems := make([]rpc.BatchElem, len(batch))
+	for i := range batch {
+		batch[i].Result = nil
+		batch[i].Error = nil
+		elems[i] = rpc.BatchElem{
+			Method: string(batch[i].Method),
+			Args:   batch[i].Params,
+			Result: &batch[i].Result,
+		}
+	}
+	if err := c.BatchCallContext(ctx, elems); err != nil {
+		return err
+	}
+	for i := range elems {
+		batch[i].Error = elems[i].Error
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/AutoArbi/go-viem/types"
+	"sync"
+	"time"
+)
+
+// batchScheduler collects concurrent requests within a short window and flushes them as a single JSON-RPC batch
+type batchScheduler struct {
+	maxSize int
+	wait    time.Duration
+	send    func(ctx context.Context, batch []BatchElem) error
+
+	mu      sync.Mutex
+	pending []*batchCall
+	timer   *time.Timer
+}
+
+// batchCall is a queued request waiting for its batch to be flushed
+type batchCall struct {
+	ctx  context.Context
+	elem BatchElem
+	done chan struct{}
+}
+
+func newBatchScheduler(maxSize int, wait time.Duration, send func(ctx context.Context, batch []BatchElem) error) *batchScheduler {
+	return &batchScheduler{
+		maxSize: maxSize,
+		wait:    wait,
+		send:    send,
+	}
+}
+
+// request queues the call and blocks until its batch was sent or ctx is done
+func (s *batchScheduler) request(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+	call := &batchCall{
+		ctx:  ctx,
+		elem: BatchElem{Method: method, Params: params},
+		done: make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.pending = append(s.pending, call)
+	if len(s.pending) >= s.maxSize {
+		calls := s.takeLocked()
+		s.mu.Unlock()
+		go s.flush(calls)
+	} else {
+		if s.timer == nil {
+			s.timer = time.AfterFunc(s.wait, s.flushPending)
+		}
+		s.mu.Unlock()
+	}
+
+	select {
+	case <-call.done:
+		return call.elem.Result, call.elem.Error
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// takeLocked removes all pending calls, s.mu must be held
+func (s *batchScheduler) takeLocked() []*batchCall {
+	calls := s.pending
+	s.pending = nil
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	return calls
+}
+
+// flushPending is fired by the timer once the wait window has passed
+func (s *batchScheduler) flushPending() {
+	s.mu.Lock()
+	calls := s.takeLocked()
+	s.mu.Unlock()
+	s.flush(calls)
+}
+
+// flush sends the calls whose callers are still waiting and hands every caller its result
+func (s *batchScheduler) flush(calls []*batchCall) {
+	live := make([]*batchCall, 0, len(calls))
+	for _, call := range calls {
+		if call.ctx.Err() != nil {
+			close(call.done)
+			continue
+		}
+		live = append(live, call)
+	}
+	if len(live) == 0 {
+		return
+	}
+
+	batch := make([]BatchElem, len(live))
+	for i, call := range live {
+		batch[i] = call.elem
+	}
+	err := s.send(context.Background(), batch)
+	for i, call := range live {
+		if err != nil {
+			call.elem.Error = err
+		} else {
+			call.elem.Result = batch[i].Result
+			call.elem.Error = batch[i].Error
+		}
+		close(call.done)
+	}
+}
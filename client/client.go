@@ -2,15 +2,16 @@ package client
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
 	"fmt"
+	verrors "github.com/AutoArbi/go-viem/errors"
 	"github.com/AutoArbi/go-viem/types"
-	"github.com/AutoArbi/go-viem/util"
+	"github.com/AutoArbi/go-viem/wallet"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
 	"math/big"
 	"time"
 )
@@ -22,31 +23,42 @@ const (
 	minRetryCount          = 0
 )
 
+// errAccountRequired is returned by wallet actions of a client without account
+var errAccountRequired = errors.New("account is required for wallet operations")
+
 // Option config function type
 type Option func(*config) error
 
 // Transport is a JSON-RPC Client interface
 type Transport interface {
 	Request(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error)
+	// BatchRequest sends all elements in one JSON-RPC array. The returned error is only
+	// set when the batch as a whole failed, per element errors are stored in BatchElem.Error
+	BatchRequest(ctx context.Context, batch []BatchElem) error
 }
 
 // Client is a JSON-RPC Client that supports fallback
 type Client struct {
-	transport       []Transport
-	privateKey      *ecdsa.PrivateKey
+	transport []Transport
+	account   wallet.Account
+	// from is the address of account
 	from            common.Address
+	nonceManager    *NonceManager
 	timeout         time.Duration
 	pollingInterval time.Duration
 	retryCount      int
+	batch           *batchScheduler
 }
 
 type config struct {
 	transport       []Transport
-	privateKey      *ecdsa.PrivateKey
-	from            common.Address
+	account         wallet.Account
+	nonceManager    *NonceManager
 	timeout         time.Duration
 	pollingInterval time.Duration
 	retryCount      int
+	batchSize       int
+	batchWait       time.Duration
 }
 
 // NewClient creates a Client and applies all options
@@ -73,14 +85,21 @@ func NewClient(opts ...Option) (*Client, error) {
 		return nil, fmt.Errorf("retry count must be >= %d", minRetryCount)
 	}
 
-	return &Client{
+	c := &Client{
 		transport:       cfg.transport,
-		privateKey:      cfg.privateKey,
-		from:            cfg.from,
+		account:         cfg.account,
+		nonceManager:    cfg.nonceManager,
 		timeout:         cfg.timeout,
 		pollingInterval: cfg.pollingInterval,
 		retryCount:      cfg.retryCount,
-	}, nil
+	}
+	if cfg.account != nil {
+		c.from = cfg.account.Address()
+	}
+	if cfg.batchSize > 0 {
+		c.batch = newBatchScheduler(cfg.batchSize, cfg.batchWait, c.BatchRequest)
+	}
+	return c, nil
 }
 
 // WithTransport adds Transport
@@ -94,15 +113,36 @@ func WithTransport(t ...Transport) Option {
 	}
 }
 
-// WithPrivateKey sets the private key
+// WithPrivateKey sets a private key account, see WithAccount
 func WithPrivateKey(privateKeyHex string) Option {
 	return func(c *config) error {
-		pk, err := crypto.HexToECDSA(privateKeyHex)
+		account, err := wallet.PrivateKeyAccountFromHex(privateKeyHex)
 		if err != nil {
 			return err
 		}
-		c.privateKey = pk
-		c.from = crypto.PubkeyToAddress(pk.PublicKey)
+		c.account = account
+		return nil
+	}
+}
+
+// WithAccount sets the account that sends transactions and signs messages
+func WithAccount(account wallet.Account) Option {
+	return func(c *config) error {
+		if account == nil {
+			return errors.New("account cannot be nil")
+		}
+		c.account = account
+		return nil
+	}
+}
+
+// WithNonceManager makes SendTransaction take the nonces of transactions without nonce from the manager
+func WithNonceManager(m *NonceManager) Option {
+	return func(c *config) error {
+		if m == nil {
+			return errors.New("nonce manager cannot be nil")
+		}
+		c.nonceManager = m
 		return nil
 	}
 }
@@ -140,8 +180,39 @@ func WithRetryCount(count int) Option {
 	}
 }
 
-// Request calls all Transports in sequence and returns the first successful result
+// WithBatching enables request coalescing: concurrent Request calls made within wait are
+// sent as a single JSON-RPC batch of at most maxSize calls
+func WithBatching(maxSize int, wait time.Duration) Option {
+	return func(c *config) error {
+		if maxSize <= 0 {
+			return errors.New("batch size must be positive")
+		}
+		if wait < 0 {
+			return errors.New("batch wait must not be negative")
+		}
+		c.batchSize = maxSize
+		c.batchWait = wait
+		return nil
+	}
+}
+
+// Account returns the account of the client, nil when none is set
+func (c *Client) Account() wallet.Account {
+	return c.account
+}
+
+// PollingInterval returns the interval used between retries and by polling watchers
+func (c *Client) PollingInterval() time.Duration {
+	return c.pollingInterval
+}
+
+// Request calls all Transports in sequence and returns the first successful result. Transport failures are
+// retried, JSON-RPC error responses are returned at once. With batching enabled the call is queued and sent as part of a JSON-RPC batch
 func (c *Client) Request(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+	if c.batch != nil {
+		return c.batch.request(ctx, method, params...)
+	}
+
 	var (
 		res     json.RawMessage
 		lastErr error
@@ -160,10 +231,17 @@ func (c *Client) Request(ctx context.Context, method types.RPCMethod, params ...
 				if lastErr == nil {
 					return res, nil
 				}
+				// a JSON-RPC error response like a revert is the node's answer and is returned without retrying
+				var rpcErr rpc.Error
+				if errors.As(lastErr, &rpcErr) {
+					return nil, lastErr
+				}
 			}
 
 			if attempt < c.retryCount {
-				time.Sleep(c.pollingInterval)
+				if err := sleepContext(ctx, c.pollingInterval); err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
@@ -171,10 +249,81 @@ func (c *Client) Request(ctx context.Context, method types.RPCMethod, params ...
 	return nil, fmt.Errorf("request failed after %d attempts: %w", c.retryCount+1, lastErr)
 }
 
+// BatchRequest sends the batch through all Transports in sequence until one succeeds.
+// Fallback and retry only apply to failures of the whole batch, per element errors are kept in the batch
+func (c *Client) BatchRequest(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	var lastErr error
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	for attempt := 0; attempt <= c.retryCount; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			for _, t := range c.transport {
+				lastErr = t.BatchRequest(ctx, batch)
+				if lastErr == nil {
+					return nil
+				}
+			}
+
+			// a JSON-RPC error response is deterministic and is returned without retrying
+			var rpcErr rpc.Error
+			if errors.As(lastErr, &rpcErr) {
+				return lastErr
+			}
+			if attempt < c.retryCount {
+				if err := sleepContext(ctx, c.pollingInterval); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("batch request failed after %d attempts: %w", c.retryCount+1, lastErr)
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Subscribe subscribes through the first Transport that implements Subscriber and falls back to the next one on failure
+func (c *Client) Subscribe(ctx context.Context, channel any, args ...any) (Subscription, error) {
+	lastErr := verrors.ErrSubscriptionNotSupported
+	for _, t := range c.transport {
+		s, ok := t.(Subscriber)
+		if !ok {
+			continue
+		}
+		sub, err := s.Subscribe(ctx, channel, args...)
+		if err == nil {
+			return sub, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
 // SendETH sends ETH
+//
+// Deprecated: use SendTransaction, which fills in the chain ID, nonce, gas and fees
 func (c *Client) SendETH(ctx context.Context, to common.Address, amount, chainID *big.Int, gasLimit, nonce uint64, maxFeePerGas, maxPriorityFeePerGas *big.Int) (common.Hash, error) {
-	if c.privateKey == nil {
-		return common.Hash{}, fmt.Errorf("private key is required for wallet operations")
+	if c.account == nil {
+		return common.Hash{}, errAccountRequired
 	}
 
 	tx := &ethTypes.DynamicFeeTx{
@@ -185,10 +334,10 @@ func (c *Client) SendETH(ctx context.Context, to common.Address, amount, chainID
 		Gas:       gasLimit,
 		GasTipCap: maxPriorityFeePerGas,
 		GasFeeCap: maxFeePerGas,
-		Data:      nil, // Optional: fill in ABI-encoded contract call
+		Data:      nil,
 	}
 
-	signedTx, err := ethTypes.SignNewTx(c.privateKey, ethTypes.NewLondonSigner(chainID), tx)
+	signedTx, err := c.account.SignTransaction(ctx, ethTypes.NewTx(tx), chainID)
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -198,36 +347,45 @@ func (c *Client) SendETH(ctx context.Context, to common.Address, amount, chainID
 	return txHash, err
 }
 
-// SignTypedData signs EIP-712 structured data
+// SignTypedData signs EIP-712 structured data with the account. v of the signature is 0 or 1 as returned by
+// crypto.Sign, unlike wallet.Account which returns 27 or 28
 func (c *Client) SignTypedData(typedDataJSON string) ([]byte, error) {
-	if c.privateKey == nil {
-		return nil, errors.New("wallet not initialized")
+	if c.account == nil {
+		return nil, errAccountRequired
 	}
-	hash, err := util.TypedDataHash(typedDataJSON)
-	if err != nil {
-		return nil, err
-	}
-	return crypto.Sign(hash.Bytes(), c.privateKey)
+	return recoveryIDSignature(c.account.SignTypedData(context.Background(), typedDataJSON))
 }
 
-// SignMessage signs a message
+// SignMessage signs an EIP-191 personal message with the account. v of the signature is 0 or 1 as returned by
+// crypto.Sign, unlike wallet.Account which returns 27 or 28
 func (c *Client) SignMessage(msg []byte) ([]byte, error) {
-	if c.privateKey == nil {
-		return nil, errors.New("wallet not initialized")
+	if c.account == nil {
+		return nil, errAccountRequired
 	}
-	msgHash := crypto.Keccak256Hash(
-		[]byte("\x19Ethereum Signed Message:\n" + fmt.Sprint(len(msg)) + string(msg)),
-	)
-	return crypto.Sign(msgHash.Bytes(), c.privateKey)
+	return recoveryIDSignature(c.account.SignMessage(context.Background(), msg))
+}
+
+// recoveryIDSignature moves v of a 65 byte signature from 27 or 28 to the recovery id 0 or 1
+func recoveryIDSignature(signature []byte, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	if len(signature) == crypto.SignatureLength && signature[crypto.RecoveryIDOffset] >= 27 {
+		signature = append([]byte{}, signature...)
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	return signature, nil
 }
 
 // SendETH1559 sends an EIP-1559 transaction
+//
+// Deprecated: use SendTransaction, which fills in the chain ID, nonce, gas and fees
 func (c *Client) SendETH1559(ctx context.Context, to common.Address,
 	amount, maxFeePerGas, maxPriorityFeePerGas, chainID *big.Int, gasLimit, nonce uint64,
 	accessList ethTypes.AccessList) (common.Hash, error) {
 
-	if c.privateKey == nil {
-		return common.Hash{}, fmt.Errorf("private key is required for wallet operations")
+	if c.account == nil {
+		return common.Hash{}, errAccountRequired
 	}
 	tx := &ethTypes.DynamicFeeTx{
 		ChainID:    chainID,
@@ -239,7 +397,7 @@ func (c *Client) SendETH1559(ctx context.Context, to common.Address,
 		Gas:        gasLimit,
 		AccessList: accessList,
 	}
-	signedTx, err := ethTypes.SignNewTx(c.privateKey, ethTypes.NewLondonSigner(chainID), tx)
+	signedTx, err := c.account.SignTransaction(ctx, ethTypes.NewTx(tx), chainID)
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -6,24 +6,36 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/AutoArbi/go-viem/types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type mockTransport struct {
-	requestFunc func(ctx context.Context, method string, params ...any) (json.RawMessage, error)
+	requestFunc func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error)
+	batchFunc   func(ctx context.Context, batch []BatchElem) error
 }
 
-func (m *mockTransport) Request(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
+func (m *mockTransport) Request(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
 	if m.requestFunc != nil {
 		return m.requestFunc(ctx, method, params...)
 	}
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockTransport) BatchRequest(ctx context.Context, batch []BatchElem) error {
+	if m.batchFunc != nil {
+		return m.batchFunc(ctx, batch)
+	}
+	return errors.New("not implemented")
+}
+
 func TestNewClient_NoTransport(t *testing.T) {
 	_, err := NewClient()
 	if err == nil {
@@ -33,7 +45,7 @@ func TestNewClient_NoTransport(t *testing.T) {
 
 func TestNewClient_WithTransport(t *testing.T) {
 	mt := &mockTransport{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
 			return json.RawMessage("\"OK\""), nil
 		},
 	}
@@ -52,7 +64,7 @@ func TestNewClient_WithTransport(t *testing.T) {
 func TestRequest_SuccessAfterRetry(t *testing.T) {
 	attempt := 0
 	mt := &mockTransport{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
 			attempt++
 			if attempt < 2 {
 				return nil, errors.New("simulated failure")
@@ -79,7 +91,7 @@ func TestRequest_SuccessAfterRetry(t *testing.T) {
 
 func TestGetNonceAndChainID(t *testing.T) {
 	mt := &mockTransport{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
 			switch method {
 			case "getNonce":
 				return json.RawMessage("\"0x1\""), nil
@@ -99,7 +111,7 @@ func TestGetNonceAndChainID(t *testing.T) {
 
 func TestSendETH_RequestError(t *testing.T) {
 	mt := &mockTransport{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
 			switch method {
 			case "eth_chainId":
 				return json.Marshal("0x1")
@@ -147,3 +159,216 @@ func TestSendETH_RequestError(t *testing.T) {
 		t.Errorf("Unexpected error message: %v", err)
 	}
 }
+
+func TestBatchRequest_RetryKeepsItemErrors(t *testing.T) {
+	attempt := 0
+	mt := &mockTransport{
+		batchFunc: func(ctx context.Context, batch []BatchElem) error {
+			attempt++
+			if attempt < 2 {
+				return errors.New("simulated failure")
+			}
+			batch[0].Result = json.RawMessage("\"0x1\"")
+			batch[1].Error = errors.New("execution reverted")
+			return nil
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithRetryCount(2), WithPollingInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	batch := []BatchElem{
+		{Method: types.GetChainID},
+		{Method: types.Call, Params: []any{map[string]any{}, "latest"}},
+	}
+	if err := cl.BatchRequest(context.Background(), batch); err != nil {
+		t.Fatalf("BatchRequest failed: %v", err)
+	}
+	if attempt != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempt)
+	}
+	if string(batch[0].Result) != "\"0x1\"" || batch[0].Error != nil {
+		t.Errorf("unexpected first element: %s, %v", batch[0].Result, batch[0].Error)
+	}
+	if batch[1].Error == nil {
+		t.Error("expected error for second element")
+	}
+}
+
+func TestRequest_NoRetryOnRPCError(t *testing.T) {
+	calls := 0
+	mt := &mockTransport{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			calls++
+			return nil, &testRevertError{data: "0x"}
+		},
+	}
+	cl, err := NewClient(WithTransport(mt, mt))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	start := time.Now()
+	_, err = cl.Request(context.Background(), types.Call, map[string]any{}, types.LATEST)
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected the JSON-RPC error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 transport call for a revert, got %d", calls)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("revert waited for retries, took %s", time.Since(start))
+	}
+}
+
+func TestBatchRequest_NoRetryOnRPCError(t *testing.T) {
+	attempt := 0
+	mt := &mockTransport{
+		batchFunc: func(ctx context.Context, batch []BatchElem) error {
+			attempt++
+			return &testRevertError{}
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithRetryCount(3), WithPollingInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if err := cl.BatchRequest(context.Background(), []BatchElem{{Method: types.GetChainID}}); err == nil {
+		t.Fatal("expected the JSON-RPC error")
+	}
+	if attempt != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempt)
+	}
+}
+
+func TestBatchRequest_RetryWaitStopsOnCancel(t *testing.T) {
+	mt := &mockTransport{
+		batchFunc: func(ctx context.Context, batch []BatchElem) error {
+			return errors.New("connection refused")
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithRetryCount(3), WithPollingInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := cl.BatchRequest(ctx, []BatchElem{{Method: types.GetChainID}}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("retry wait ignored the context, took %s", time.Since(start))
+	}
+}
+
+type testEthService struct{}
+
+func (s *testEthService) ChainId() string { return "0x1" }
+
+func (s *testEthService) Call() (string, error) { return "", errors.New("execution reverted") }
+
+func TestHTTPTransport_BatchRequest(t *testing.T) {
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", new(testEthService)); err != nil {
+		t.Fatalf("register service failed: %v", err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	transport, err := NewHTTPTransport(httpServer.URL)
+	if err != nil {
+		t.Fatalf("NewHTTPTransport failed: %v", err)
+	}
+	batch := []BatchElem{
+		{Method: types.GetChainID},
+		{Method: types.Call},
+		{Method: types.GetChainID},
+	}
+	if err := transport.BatchRequest(context.Background(), batch); err != nil {
+		t.Fatalf("BatchRequest failed: %v", err)
+	}
+	for _, i := range []int{0, 2} {
+		if batch[i].Error != nil || string(batch[i].Result) != "\"0x1\"" {
+			t.Errorf("element %d: unexpected result %s, error %v", i, batch[i].Result, batch[i].Error)
+		}
+	}
+	if batch[1].Error == nil || !strings.Contains(batch[1].Error.Error(), "execution reverted") {
+		t.Errorf("element 1: expected execution reverted, got %v", batch[1].Error)
+	}
+}
+
+func TestRequest_Batching(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches []int
+	)
+	mt := &mockTransport{
+		batchFunc: func(ctx context.Context, batch []BatchElem) error {
+			mu.Lock()
+			batches = append(batches, len(batch))
+			mu.Unlock()
+			for i := range batch {
+				batch[i].Result, _ = json.Marshal(batch[i].Params[0])
+			}
+			return nil
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithBatching(20, 20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := cl.Request(context.Background(), types.GetBalance, fmt.Sprintf("0x%x", i))
+			if err != nil {
+				errs <- err
+				return
+			}
+			if string(res) != fmt.Sprintf("\"0x%x\"", i) {
+				errs <- fmt.Errorf("request %d got result %s", i, res)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	total := 0
+	for _, size := range batches {
+		if size > 20 {
+			t.Errorf("batch size %d exceeds max size", size)
+		}
+		total += size
+	}
+	if total != 50 || len(batches) >= 50 {
+		t.Errorf("expected 50 requests coalesced into batches, got %v", batches)
+	}
+}
+
+func TestRequest_BatchingContextCanceled(t *testing.T) {
+	mt := &mockTransport{
+		batchFunc: func(ctx context.Context, batch []BatchElem) error {
+			t.Error("canceled request must not be sent")
+			return nil
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithBatching(10, 50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := cl.Request(ctx, types.GetChainID); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// WriteContractOptions configures WriteContract
+type WriteContractOptions struct {
+	// Value is the amount of wei sent with the call
+	Value *big.Int
+	// Simulate executes the call with eth_call before sending it, so a revert is returned without sending a transaction
+	Simulate bool
+	// ErrorABIs are decoded against in addition to the contract's ABI when the call reverts with a custom error,
+	// e.g. the ABIs of contracts called by the target
+	ErrorABIs []string
+	// Transaction sets the nonce, gas, fees or type of the transaction, its To, Data and Value are replaced
+	Transaction types.TransactionRequest
+}
+
+// WriteContract encodes a call to a function of the contract at address, the ABI may be JSON or human-readable,
+// and sends it as a transaction signed by the client's account. Reverts found by the simulation or the gas
+// estimation are returned as *util.ContractRevertError with custom errors decoded against the contract's ABI
+// and opts.ErrorABIs
+// method: eth_sendRawTransaction
+func (c *Client) WriteContract(ctx context.Context, contractABI string, address common.Address, functionName string, args []any, opts *WriteContractOptions) (common.Hash, error) {
+	if opts == nil {
+		opts = &WriteContractOptions{}
+	}
+	parsed, err := util.ParseABI(contractABI)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	abis := []*abi.ABI{parsed}
+	for _, errorABI := range opts.ErrorABIs {
+		errorParsed, err := util.ParseABI(errorABI)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		abis = append(abis, errorParsed)
+	}
+	data, err := parsed.Pack(functionName, args...)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode %s call: %w", functionName, err)
+	}
+
+	req := opts.Transaction
+	req.To = &address
+	req.Data = data
+	req.Value = opts.Value
+	if req.From == (common.Address{}) {
+		req.From = c.from
+	}
+
+	if opts.Simulate {
+		call := types.TransactionRequest{From: req.From, To: req.To, Data: req.Data, Value: req.Value}
+		if _, err := c.Request(ctx, types.Call, call, types.LATEST); err != nil {
+			if revertErr := util.RevertFromError(err, abis...); revertErr != nil {
+				return common.Hash{}, revertErr
+			}
+			return common.Hash{}, fmt.Errorf("failed to simulate %s call: %w", functionName, err)
+		}
+	}
+
+	hash, err := c.SendTransaction(ctx, &req)
+	if err != nil {
+		if revertErr := util.RevertFromError(err, abis...); revertErr != nil {
+			return common.Hash{}, revertErr
+		}
+		return common.Hash{}, err
+	}
+	return hash, nil
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const routerABI = `
+function swapExactETHForTokens(uint256 amountOutMin, address[] path, address to, uint256 deadline) payable returns (uint256[] amounts)
+error Expired(uint256 deadline)
+`
+
+// testRevertError mimics the error geth returns for reverted calls
+type testRevertError struct {
+	data string
+}
+
+func (e *testRevertError) Error() string          { return "execution reverted" }
+func (e *testRevertError) ErrorCode() int         { return 3 }
+func (e *testRevertError) ErrorData() interface{} { return e.data }
+
+func TestWriteContract(t *testing.T) {
+	parsed, _ := util.ParseABI(routerABI)
+	router := common.HexToAddress("0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D")
+	path := []common.Address{common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"), common.HexToAddress("0x1")}
+
+	var sent *ethTypes.Transaction
+	node := newNodeTransport(t, big.NewInt(10e9), &sent)
+	simulated := 0
+	mt := &mockTransport{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.Call {
+				return node.requestFunc(ctx, method, params...)
+			}
+			simulated++
+			call := params[0].(types.TransactionRequest)
+			values, err := parsed.Methods["swapExactETHForTokens"].Inputs.Unpack(call.Data[4:])
+			if err != nil {
+				return nil, err
+			}
+			if deadline := values[3].(*big.Int); deadline.Int64() < 100 {
+				expired := parsed.Errors["Expired"]
+				data, _ := expired.Inputs.Pack(deadline)
+				return nil, &testRevertError{data: hexutil.Encode(append(expired.ID[:4], data...))}
+			}
+			return json.RawMessage(`"0x"`), nil
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithPrivateKey(testPrivateKey), WithRetryCount(0))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	args := []any{big.NewInt(1), path, cl.from, big.NewInt(1000)}
+	hash, err := cl.WriteContract(context.Background(), routerABI, router, "swapExactETHForTokens", args, &WriteContractOptions{
+		Value:    big.NewInt(1e18),
+		Simulate: true,
+	})
+	if err != nil {
+		t.Fatalf("WriteContract failed: %v", err)
+	}
+	want, _ := parsed.Pack("swapExactETHForTokens", args...)
+	if sent == nil || sent.Hash() != hash || *sent.To() != router || !bytes.Equal(sent.Data(), want) || sent.Value().Cmp(big.NewInt(1e18)) != 0 {
+		t.Fatalf("unexpected transaction sent: %v", sent)
+	}
+	if simulated != 1 {
+		t.Errorf("expected 1 simulation, got %d", simulated)
+	}
+
+	sent = nil
+	args[3] = big.NewInt(10)
+	_, err = cl.WriteContract(context.Background(), routerABI, router, "swapExactETHForTokens", args, &WriteContractOptions{Simulate: true})
+	var revertErr *util.ContractRevertError
+	if !errors.As(err, &revertErr) || revertErr.Name != "Expired" || revertErr.Args[0].(*big.Int).Int64() != 10 {
+		t.Fatalf("expected Expired revert, got %v", err)
+	}
+	if sent != nil {
+		t.Error("no transaction must be sent after a failed simulation")
+	}
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"sync"
+)
+
+// NonceManager hands out the nonces of accounts locally, so concurrent senders of one account do not race on the
+// pending transaction count. Nonces are tracked per chain and address and start from the pending transaction count.
+// Share one NonceManager between clients of the same chain and account
+type NonceManager struct {
+	mu     sync.Mutex
+	nonces map[nonceKey]*accountNonce
+}
+
+type nonceKey struct {
+	chainID string
+	address common.Address
+}
+
+// accountNonce is the next nonce of an account, its lock is held while the nonce is fetched
+type accountNonce struct {
+	mu   sync.Mutex
+	next uint64
+	// released are nonces below next that were given back and are handed out before next
+	released map[uint64]struct{}
+	// inFlight are consumed nonces whose broadcast has not finished yet
+	inFlight map[uint64]struct{}
+	synced   bool
+	// keepAhead makes the next fetch keep next when it is ahead of the node, as nonces below it may still be in flight
+	keepAhead bool
+}
+
+// NewNonceManager creates a NonceManager
+func NewNonceManager() *NonceManager {
+	return &NonceManager{nonces: make(map[nonceKey]*accountNonce)}
+}
+
+// Consume reserves the next nonce of the address on the chain. The first call and the first call after Reset
+// start from the nonce returned by fetch, usually the pending transaction count. Released nonces are handed out
+// again first, lowest first
+func (m *NonceManager) Consume(ctx context.Context, chainID *big.Int, address common.Address, fetch func(ctx context.Context) (uint64, error)) (uint64, error) {
+	account := m.account(chainID, address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	if !account.synced {
+		fetched, err := fetch(ctx)
+		if err != nil {
+			return 0, err
+		}
+		// without broadcasts in flight the node knows every nonce that was used, nonces from the fetched one
+		// up to next were dropped and are handed out again
+		keepAhead := account.keepAhead && len(account.inFlight) > 0
+		if !keepAhead || fetched > account.next {
+			account.next = fetched
+		}
+		for nonce := range account.released {
+			if !keepAhead || nonce < fetched {
+				delete(account.released, nonce)
+			}
+		}
+		account.synced, account.keepAhead = true, false
+	}
+
+	nonce := account.next
+	if len(account.released) > 0 {
+		for released := range account.released {
+			nonce = min(nonce, released)
+		}
+		delete(account.released, nonce)
+	} else {
+		account.next++
+	}
+	if account.inFlight == nil {
+		account.inFlight = make(map[uint64]struct{})
+	}
+	account.inFlight[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// Sent marks a consumed nonce as broadcast. Consumed nonces count as in flight until they are passed to Sent or
+// Release, a resync only detects dropped nonces while none are in flight
+func (m *NonceManager) Sent(chainID *big.Int, address common.Address, nonce uint64) {
+	account := m.account(chainID, address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+	delete(account.inFlight, nonce)
+}
+
+// Release gives back a consumed nonce that was not broadcast, so the next Consume hands it out again and
+// no gap is left behind
+func (m *NonceManager) Release(chainID *big.Int, address common.Address, nonce uint64) {
+	account := m.account(chainID, address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+
+	delete(account.inFlight, nonce)
+	if nonce >= account.next {
+		return
+	}
+	if account.released == nil {
+		account.released = make(map[uint64]struct{})
+	}
+	account.released[nonce] = struct{}{}
+	// hand back the top of the range so released nonces only hold gaps
+	for account.next > 0 {
+		if _, ok := account.released[account.next-1]; !ok {
+			return
+		}
+		account.next--
+		delete(account.released, account.next)
+	}
+}
+
+// Reset makes the next Consume start over from the nonce fetched from the node, dropping the local state. It is
+// called when the node reports a nonce too high
+func (m *NonceManager) Reset(chainID *big.Int, address common.Address) {
+	account := m.account(chainID, address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+	account.synced, account.keepAhead = false, false
+}
+
+// resync makes the next Consume fetch the nonce from the node again. While broadcasts are in flight it continues
+// from the larger of the fetched nonce and the next local nonce, so their nonces are not handed out twice. Without
+// broadcasts in flight a fetched nonce below the next local nonce means transactions were dropped, and Consume
+// starts over from the fetched nonce
+func (m *NonceManager) resync(chainID *big.Int, address common.Address) {
+	account := m.account(chainID, address)
+	account.mu.Lock()
+	defer account.mu.Unlock()
+	if account.synced {
+		account.synced, account.keepAhead = false, true
+	}
+}
+
+func (m *NonceManager) account(chainID *big.Int, address common.Address) *accountNonce {
+	key := nonceKey{chainID: chainID.String(), address: address}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	account, ok := m.nonces[key]
+	if !ok {
+		account = &accountNonce{}
+		m.nonces[key] = account
+	}
+	return account
+}
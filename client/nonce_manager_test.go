@@ -0,0 +1,293 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestNonceManager_Consume(t *testing.T) {
+	m := NewNonceManager()
+	chainID, address := big.NewInt(1), common.HexToAddress("0x1")
+	var fetched atomic.Int32
+	fetch := func(ctx context.Context) (uint64, error) {
+		fetched.Add(1)
+		return 5, nil
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		nonces = make(map[uint64]bool)
+	)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := m.Consume(context.Background(), chainID, address, fetch)
+			if err != nil {
+				t.Errorf("Consume failed: %v", err)
+				return
+			}
+			mu.Lock()
+			nonces[nonce] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if fetched.Load() != 1 || len(nonces) != 100 || !nonces[5] || !nonces[104] {
+		t.Fatalf("expected nonces 5 to 104 from one fetch, fetched %d times, got %d nonces", fetched.Load(), len(nonces))
+	}
+
+	// released nonces are handed out again, lowest first
+	m.Release(chainID, address, 104)
+	m.Release(chainID, address, 50)
+	m.Release(chainID, address, 20)
+	for _, want := range []uint64{20, 50, 104, 105} {
+		if nonce, _ := m.Consume(context.Background(), chainID, address, fetch); nonce != want {
+			t.Errorf("expected nonce %d, got %d", want, nonce)
+		}
+	}
+
+	// a resync keeps the local nonce when it is ahead of the node, a reset starts over
+	m.resync(chainID, address)
+	if nonce, _ := m.Consume(context.Background(), chainID, address, fetch); nonce != 106 || fetched.Load() != 2 {
+		t.Errorf("expected nonce 106 after resync, got %d after %d fetches", nonce, fetched.Load())
+	}
+	m.Reset(chainID, address)
+	if nonce, _ := m.Consume(context.Background(), chainID, address, fetch); nonce != 5 || fetched.Load() != 3 {
+		t.Errorf("expected nonce 5 after reset, got %d after %d fetches", nonce, fetched.Load())
+	}
+
+	// other chains are tracked separately
+	if nonce, _ := m.Consume(context.Background(), big.NewInt(10), address, fetch); nonce != 5 {
+		t.Errorf("expected nonce 5 on another chain, got %d", nonce)
+	}
+}
+
+func TestSendTransaction_NonceManager(t *testing.T) {
+	node := newNodeTransport(t, big.NewInt(10e9), nil)
+	var (
+		mu        sync.Mutex
+		sent      = make(map[uint64]bool)
+		pending   = uint64(3)
+		fetched   int
+		rejectLow bool
+	)
+	mt := &mockTransport{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch method {
+			case types.GetTransactionCount:
+				fetched++
+				return json.Marshal(hexutil.Uint64(pending))
+			case types.SendRawTransaction:
+				if rejectLow {
+					rejectLow = false
+					return nil, testNodeError("nonce too low: next nonce 40, tx nonce 23")
+				}
+				tx := new(ethTypes.Transaction)
+				if err := tx.UnmarshalBinary(hexutil.MustDecode(params[0].(string))); err != nil {
+					return nil, err
+				}
+				sent[tx.Nonce()] = true
+				return json.Marshal(tx.Hash())
+			}
+			return node.requestFunc(ctx, method, params...)
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithPrivateKey(testPrivateKey), WithNonceManager(NewNonceManager()), WithRetryCount(0))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	to := common.HexToAddress("0x1234")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cl.SendTransaction(context.Background(), &types.TransactionRequest{To: &to}); err != nil {
+				t.Errorf("SendTransaction failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if len(sent) != 20 || !sent[3] || !sent[22] || fetched != 1 {
+		t.Fatalf("expected nonces 3 to 22 from one fetch, fetched %d times, got %v", fetched, sent)
+	}
+
+	mu.Lock()
+	rejectLow, pending = true, 40
+	mu.Unlock()
+	if _, err := cl.SendTransaction(context.Background(), &types.TransactionRequest{To: &to}); err == nil {
+		t.Fatal("expected nonce too low error")
+	}
+	if _, err := cl.SendTransaction(context.Background(), &types.TransactionRequest{To: &to}); err != nil {
+		t.Fatalf("SendTransaction failed: %v", err)
+	}
+	if !sent[40] || fetched != 2 {
+		t.Errorf("expected a resynchronized nonce 40, fetched %d times", fetched)
+	}
+}
+
+func TestSendTransaction_NonceManagerResyncInFlight(t *testing.T) {
+	node := newNodeTransport(t, big.NewInt(10e9), nil)
+	var (
+		mu       sync.Mutex
+		sent     = make(map[uint64]int)
+		inFlight sync.WaitGroup
+		gate     = make(chan struct{})
+	)
+	inFlight.Add(5)
+	mt := &mockTransport{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			switch method {
+			case types.GetTransactionCount:
+				// the node has not seen the sends in flight yet
+				return json.RawMessage(`"0x3"`), nil
+			case types.SendRawTransaction:
+				tx := new(ethTypes.Transaction)
+				if err := tx.UnmarshalBinary(hexutil.MustDecode(params[0].(string))); err != nil {
+					return nil, err
+				}
+				switch {
+				case tx.Nonce() < 8:
+					inFlight.Done()
+					<-gate
+				case tx.Nonce() == 8:
+					return nil, testNodeError("nonce too low: next nonce 3, tx nonce 8")
+				}
+				mu.Lock()
+				sent[tx.Nonce()]++
+				mu.Unlock()
+				return json.Marshal(tx.Hash())
+			}
+			return node.requestFunc(ctx, method, params...)
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithPrivateKey(testPrivateKey), WithNonceManager(NewNonceManager()), WithRetryCount(0))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	to := common.HexToAddress("0x1234")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cl.SendTransaction(context.Background(), &types.TransactionRequest{To: &to}); err != nil {
+				t.Errorf("SendTransaction failed: %v", err)
+			}
+		}()
+	}
+	inFlight.Wait()
+
+	if _, err := cl.SendTransaction(context.Background(), &types.TransactionRequest{To: &to}); err == nil {
+		t.Fatal("expected nonce too low error")
+	}
+	if _, err := cl.SendTransaction(context.Background(), &types.TransactionRequest{To: &to}); err != nil {
+		t.Fatalf("SendTransaction failed: %v", err)
+	}
+	close(gate)
+	wg.Wait()
+
+	for _, nonce := range []uint64{3, 4, 5, 6, 7, 9} {
+		if sent[nonce] != 1 {
+			t.Errorf("expected nonce %d to be sent once, got %d", nonce, sent[nonce])
+		}
+	}
+	if len(sent) != 6 {
+		t.Errorf("expected the nonces 3 to 7 and 9, got %v", sent)
+	}
+}
+
+func TestSendTransaction_NonceManagerBroadcastErrors(t *testing.T) {
+	node := newNodeTransport(t, big.NewInt(10e9), nil)
+	var (
+		pending  = uint64(7)
+		sendErr  error
+		received bool
+		nonces   []uint64
+	)
+	mt := &mockTransport{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			switch method {
+			case types.GetTransactionCount:
+				return json.Marshal(hexutil.Uint64(pending))
+			case types.SendRawTransaction:
+			default:
+				return node.requestFunc(ctx, method, params...)
+			}
+			tx := new(ethTypes.Transaction)
+			if err := tx.UnmarshalBinary(hexutil.MustDecode(params[0].(string))); err != nil {
+				return nil, err
+			}
+			nonces = append(nonces, tx.Nonce())
+			if sendErr == nil || received {
+				pending = tx.Nonce() + 1
+			}
+			if sendErr != nil {
+				return nil, sendErr
+			}
+			return json.Marshal(tx.Hash())
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithPrivateKey(testPrivateKey), WithNonceManager(NewNonceManager()), WithRetryCount(0))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	to := common.HexToAddress("0x1234")
+	send := func(err error, reached bool) error {
+		sendErr, received = err, reached
+		_, err = cl.SendTransaction(context.Background(), &types.TransactionRequest{To: &to})
+		return err
+	}
+
+	// a rejected transaction gives its nonce back
+	if err := send(&testRevertError{}, false); !errors.Is(err, verrors.ErrTransactionRejected) {
+		t.Errorf("expected ErrTransactionRejected, got %v", err)
+	}
+	send(nil, false)
+	// after a transport error the pending nonce of the node tells whether the transaction arrived
+	send(errors.New("read tcp: connection reset by peer"), false)
+	send(nil, false)
+	send(errors.New("read tcp: connection reset by peer"), true)
+	send(nil, false)
+	// a dropped transaction is detected by the resync after the next failed broadcast
+	pending = 10
+	send(testNodeError("insufficient funds for gas * price + value"), false)
+	send(nil, false)
+	if want := []uint64{7, 7, 8, 8, 9, 10, 11, 10}; !slices.Equal(nonces, want) {
+		t.Errorf("expected nonces %v, got %v", want, nonces)
+	}
+
+	// a transaction with the nonce is in the mempool, the nonce is not rejected
+	err = classifyBroadcastError(testNodeError("replacement transaction underpriced"))
+	if !errors.Is(err, verrors.ErrTransactionUnderpriced) || errors.Is(err, verrors.ErrTransactionRejected) {
+		t.Errorf("expected an underpriced error that is not rejected, got %v", err)
+	}
+	err = classifyBroadcastError(testNodeError("nonce too low: next nonce 3, tx nonce 2"))
+	if !errors.Is(err, verrors.ErrNonceTooLow) || !errors.Is(err, verrors.ErrTransactionRejected) || err.Error() != "nonce too low: next nonce 3, tx nonce 2" {
+		t.Errorf("expected a rejected nonce too low error, got %v", err)
+	}
+}
+
+// testNodeError is a mempool error of a node, which answers them with the generic server error code
+type testNodeError string
+
+func (e testNodeError) Error() string  { return string(e) }
+func (e testNodeError) ErrorCode() int { return -32000 }
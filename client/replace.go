@@ -0,0 +1,260 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AutoArbi/go-viem/transfer"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+const (
+	// DefaultFeeBumpPercent is the minimum fee increase of replacements nodes accept
+	DefaultFeeBumpPercent = 10
+	// blobFeeBumpPercent is the minimum blob fee increase of blob transaction replacements
+	blobFeeBumpPercent = 100
+)
+
+// ReplacementOptions configures the replacement of pending transactions
+type ReplacementOptions struct {
+	// FeeBumpPercent is the increase of the gas price, tip and fee cap over the original transaction,
+	// default and minimum is DefaultFeeBumpPercent. The blob fee is always doubled.
+	// The current network fees are used instead when they are higher
+	FeeBumpPercent int
+}
+
+// Replacement is a transaction sent with the nonce of a pending transaction to replace it
+type Replacement struct {
+	// Hash is the hash of the replacement
+	Hash common.Hash
+	// Replaced is the hash of the original transaction
+	Replaced common.Hash
+	// Request is the replacement as it was signed
+	Request *types.TransactionRequest
+}
+
+// SpeedUpTransaction rebroadcasts the pending transaction with the hash with the same payload and nonce and bumped fees.
+// Blob transactions have to be replaced with SpeedUpRequest, as nodes do not return their sidecar
+// method: eth_sendRawTransaction
+func (c *Client) SpeedUpTransaction(ctx context.Context, hash common.Hash, opts *ReplacementOptions) (*Replacement, error) {
+	original, err := c.pendingTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.replace(ctx, original, hash, false, opts)
+}
+
+// CancelTransaction replaces the pending transaction with the hash with a zero value transfer to the sender itself
+// with the same nonce and bumped fees. Blob transactions have to be cancelled with CancelRequest
+// method: eth_sendRawTransaction
+func (c *Client) CancelTransaction(ctx context.Context, hash common.Hash, opts *ReplacementOptions) (*Replacement, error) {
+	original, err := c.pendingTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.replace(ctx, original, hash, true, opts)
+}
+
+// SpeedUpRequest rebroadcasts a prepared request that was sent before with bumped fees. Replaced is the hash of
+// the request signed again, which is the hash of the sent transaction for deterministic signers like PrivateKeyAccount
+// method: eth_sendRawTransaction
+func (c *Client) SpeedUpRequest(ctx context.Context, req *types.TransactionRequest, opts *ReplacementOptions) (*Replacement, error) {
+	hash, err := c.preparedHash(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.replace(ctx, req, hash, false, opts)
+}
+
+// CancelRequest replaces a prepared request that was sent before with a zero value transfer to the sender itself,
+// see SpeedUpRequest for Replaced
+// method: eth_sendRawTransaction
+func (c *Client) CancelRequest(ctx context.Context, req *types.TransactionRequest, opts *ReplacementOptions) (*Replacement, error) {
+	hash, err := c.preparedHash(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return c.replace(ctx, req, hash, true, opts)
+}
+
+// pendingTransaction gets a pending transaction of the account as request
+// method: eth_getTransactionByHash
+func (c *Client) pendingTransaction(ctx context.Context, hash common.Hash) (*types.TransactionRequest, error) {
+	if c.account == nil {
+		return nil, errAccountRequired
+	}
+	res, err := c.Request(ctx, types.GetTransactionByHash, hash.Hex())
+	if err != nil {
+		return nil, err
+	}
+	tx, err := transfer.NewRPCResponseTransfer().TransferTransaction(res)
+	if err != nil {
+		return nil, err
+	}
+	if tx.BlockHash != nil {
+		return nil, fmt.Errorf("transaction %s is already included in a block", hash)
+	}
+	if tx.From != c.from {
+		return nil, fmt.Errorf("transaction %s is sent by %s, not by the account %s", hash, tx.From, c.from)
+	}
+	if tx.Type == ethTypes.BlobTxType {
+		return nil, fmt.Errorf("blob transaction %s can only be replaced from its request with sidecar", hash)
+	}
+
+	txType, nonce := tx.Type, tx.Nonce
+	return &types.TransactionRequest{
+		From:                 tx.From,
+		To:                   tx.To,
+		Value:                tx.Value,
+		Data:                 tx.Input,
+		Nonce:                &nonce,
+		Gas:                  tx.Gas,
+		ChainID:              tx.ChainID,
+		Type:                 &txType,
+		GasPrice:             legacyGasPrice(tx),
+		MaxFeePerGas:         tx.MaxFeePerGas,
+		MaxPriorityFeePerGas: tx.MaxPriorityFeePerGas,
+		AccessList:           tx.AccessList,
+		AuthorizationList:    tx.AuthorizationList,
+	}, nil
+}
+
+// legacyGasPrice returns the gas price of legacy and EIP-2930 transactions, for other types GasPrice is the
+// effective gas price
+func legacyGasPrice(tx *types.Transaction) *big.Int {
+	if tx.Type == ethTypes.LegacyTxType || tx.Type == ethTypes.AccessListTxType {
+		return tx.GasPrice
+	}
+	return nil
+}
+
+// preparedHash signs a prepared request to get the hash it was sent with
+func (c *Client) preparedHash(ctx context.Context, req *types.TransactionRequest) (common.Hash, error) {
+	if c.account == nil {
+		return common.Hash{}, errAccountRequired
+	}
+	if req.From != (common.Address{}) && req.From != c.from {
+		return common.Hash{}, fmt.Errorf("cannot replace a transaction of %s with the account %s", req.From, c.from)
+	}
+	txData, err := req.TxData()
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("request must be prepared: %w", err)
+	}
+	signedTx, err := c.account.SignTransaction(ctx, ethTypes.NewTx(txData), req.ChainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return signedTx.Hash(), nil
+}
+
+// replace sends the replacement of the original transaction, a speed-up or a cancellation
+func (c *Client) replace(ctx context.Context, original *types.TransactionRequest, originalHash common.Hash, cancel bool, opts *ReplacementOptions) (*Replacement, error) {
+	if opts == nil {
+		opts = &ReplacementOptions{}
+	}
+	bump := opts.FeeBumpPercent
+	if bump == 0 {
+		bump = DefaultFeeBumpPercent
+	}
+	if bump < DefaultFeeBumpPercent {
+		return nil, fmt.Errorf("fee bump of %d%% is below the minimum of %d%%", bump, DefaultFeeBumpPercent)
+	}
+	txType, ok := original.InferType()
+	if !ok || original.Nonce == nil || original.ChainID == nil {
+		return nil, errors.New("the original transaction must have a type, nonce and chain id")
+	}
+
+	replacement := *original
+	replacement.From = c.from
+	replacement.Type = &txType
+	if cancel {
+		// a cancellation keeps the type so it replaces blob transactions, without the authorizations of EIP-7702
+		// and with the gas estimated for the self-transfer
+		if txType == ethTypes.SetCodeTxType {
+			txType = ethTypes.DynamicFeeTxType
+		}
+		self := c.from
+		replacement = types.TransactionRequest{
+			From:                self,
+			To:                  &self,
+			Value:               new(big.Int),
+			Nonce:               original.Nonce,
+			ChainID:             original.ChainID,
+			Type:                &txType,
+			BlobVersionedHashes: original.BlobVersionedHashes,
+			Sidecar:             original.Sidecar,
+		}
+	}
+	if err := c.bumpFees(ctx, &replacement, original, bump); err != nil {
+		return nil, err
+	}
+
+	prepared, err := c.PrepareTransactionRequest(ctx, &replacement)
+	if err != nil {
+		return nil, err
+	}
+	txData, err := prepared.TxData()
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := c.account.SignTransaction(ctx, ethTypes.NewTx(txData), prepared.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := c.sendSignedTransaction(ctx, signedTx)
+	if err != nil {
+		return nil, err
+	}
+	return &Replacement{Hash: hash, Replaced: originalHash, Request: prepared}, nil
+}
+
+// bumpFees sets the fees of the replacement to the fees of the original raised by bump percent, or to the current
+// network fees when they are higher
+func (c *Client) bumpFees(ctx context.Context, replacement, original *types.TransactionRequest, bump int) error {
+	current := types.TransactionRequest{Type: replacement.Type}
+	if err := c.prepareFees(ctx, &current); err != nil {
+		return err
+	}
+
+	switch *replacement.Type {
+	case ethTypes.LegacyTxType, ethTypes.AccessListTxType:
+		if original.GasPrice == nil {
+			return errors.New("the original transaction has no gas price")
+		}
+		replacement.GasPrice = maxBig(bumpFee(original.GasPrice, bump), current.GasPrice)
+		return nil
+	}
+	if original.MaxFeePerGas == nil || original.MaxPriorityFeePerGas == nil {
+		return errors.New("the original transaction has no max fee per gas and max priority fee per gas")
+	}
+	replacement.MaxPriorityFeePerGas = maxBig(bumpFee(original.MaxPriorityFeePerGas, bump), current.MaxPriorityFeePerGas)
+	replacement.MaxFeePerGas = maxBig(bumpFee(original.MaxFeePerGas, bump), current.MaxFeePerGas, replacement.MaxPriorityFeePerGas)
+	if *replacement.Type == ethTypes.BlobTxType {
+		if original.MaxFeePerBlobGas == nil {
+			return errors.New("the original transaction has no max fee per blob gas")
+		}
+		replacement.MaxFeePerBlobGas = maxBig(bumpFee(original.MaxFeePerBlobGas, blobFeeBumpPercent), current.MaxFeePerBlobGas)
+	}
+	return nil
+}
+
+// bumpFee raises a fee by percent, rounding up
+func bumpFee(fee *big.Int, percent int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(int64(100+percent)))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Quo(bumped, big.NewInt(100))
+}
+
+// maxBig returns the largest of the non nil values
+func maxBig(values ...*big.Int) *big.Int {
+	var largest *big.Int
+	for _, v := range values {
+		if v != nil && (largest == nil || v.Cmp(largest) > 0) {
+			largest = v
+		}
+	}
+	return new(big.Int).Set(largest)
+}
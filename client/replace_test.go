@@ -0,0 +1,112 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestSpeedUpAndCancelTransaction(t *testing.T) {
+	var sent *ethTypes.Transaction
+	node := newNodeTransport(t, big.NewInt(10e9), &sent)
+	to := common.HexToAddress("0x1234")
+	pending := types.Transaction{
+		Hash:                 common.HexToHash("0xabc"),
+		Type:                 ethTypes.DynamicFeeTxType,
+		ChainID:              big.NewInt(1),
+		To:                   &to,
+		Nonce:                9,
+		Gas:                  50000,
+		Value:                big.NewInt(5),
+		Input:                []byte{0xde, 0xad},
+		MaxFeePerGas:         big.NewInt(20e9),
+		MaxPriorityFeePerGas: big.NewInt(2e9),
+		GasPrice:             big.NewInt(20e9),
+	}
+	mined := false
+	mt := &mockTransport{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method == types.GetTransactionByHash {
+				tx := pending
+				if mined {
+					blockHash := common.HexToHash("0x1")
+					tx.BlockHash = &blockHash
+				}
+				return json.Marshal(tx)
+			}
+			return node.requestFunc(ctx, method, params...)
+		},
+	}
+	cl, err := NewClient(WithTransport(mt), WithPrivateKey(testPrivateKey), WithRetryCount(0))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	pending.From = cl.from
+
+	replacement, err := cl.SpeedUpTransaction(context.Background(), pending.Hash, nil)
+	if err != nil {
+		t.Fatalf("SpeedUpTransaction failed: %v", err)
+	}
+	if replacement.Replaced != pending.Hash || replacement.Hash != sent.Hash() {
+		t.Errorf("unexpected replacement %+v", replacement)
+	}
+	// the bumped fees are above the current fees of 1 gwei tip and 13 gwei fee cap
+	if sent.Nonce() != 9 || sent.Gas() != 50000 || !bytes.Equal(sent.Data(), pending.Input) || sent.Value().Int64() != 5 ||
+		sent.GasTipCap().Int64() != 2.2e9 || sent.GasFeeCap().Int64() != 22e9 {
+		t.Errorf("unexpected speed-up: nonce %d, gas %d, tip %v, fee cap %v", sent.Nonce(), sent.Gas(), sent.GasTipCap(), sent.GasFeeCap())
+	}
+
+	if _, err := cl.CancelTransaction(context.Background(), pending.Hash, &ReplacementOptions{FeeBumpPercent: 50}); err != nil {
+		t.Fatalf("CancelTransaction failed: %v", err)
+	}
+	if sent.Nonce() != 9 || *sent.To() != cl.from || sent.Value().Sign() != 0 || len(sent.Data()) != 0 || sent.Gas() != 21000 ||
+		sent.GasTipCap().Int64() != 3e9 || sent.GasFeeCap().Int64() != 30e9 {
+		t.Errorf("unexpected cancellation: to %s, value %v, tip %v, fee cap %v", sent.To(), sent.Value(), sent.GasTipCap(), sent.GasFeeCap())
+	}
+
+	if _, err := cl.SpeedUpTransaction(context.Background(), pending.Hash, &ReplacementOptions{FeeBumpPercent: 5}); err == nil {
+		t.Error("expected error for a fee bump below 10%")
+	}
+	mined = true
+	if _, err := cl.SpeedUpTransaction(context.Background(), pending.Hash, nil); err == nil {
+		t.Error("expected error for a mined transaction")
+	}
+}
+
+func TestSpeedUpRequest_Blob(t *testing.T) {
+	var sent *ethTypes.Transaction
+	cl, err := NewClient(WithTransport(newNodeTransport(t, big.NewInt(10e9), &sent)), WithPrivateKey(testPrivateKey))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	to := common.HexToAddress("0x1234")
+	nonce := uint64(4)
+	req := &types.TransactionRequest{
+		To:                   &to,
+		Nonce:                &nonce,
+		Gas:                  21000,
+		ChainID:              big.NewInt(1),
+		MaxFeePerGas:         big.NewInt(30e9),
+		MaxPriorityFeePerGas: big.NewInt(2e9),
+		MaxFeePerBlobGas:     big.NewInt(100),
+		BlobVersionedHashes:  []common.Hash{{0x01}},
+	}
+	replacement, err := cl.SpeedUpRequest(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("SpeedUpRequest failed: %v", err)
+	}
+	original, _ := req.TxData()
+	signed, _ := cl.account.SignTransaction(context.Background(), ethTypes.NewTx(original), big.NewInt(1))
+	if replacement.Replaced != signed.Hash() {
+		t.Errorf("expected replaced hash %s, got %s", signed.Hash(), replacement.Replaced)
+	}
+	if sent.Type() != ethTypes.BlobTxType || sent.Nonce() != 4 || sent.BlobGasFeeCap().Int64() != 200 || sent.GasFeeCap().Int64() != 33e9 {
+		t.Errorf("unexpected speed-up: type %d, blob fee cap %v, fee cap %v", sent.Type(), sent.BlobGasFeeCap(), sent.GasFeeCap())
+	}
+}
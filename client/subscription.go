@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Subscription is an active eth_subscribe subscription
+type Subscription interface {
+	// Err returns the subscription error channel. It receives a value when the subscription
+	// fails and is closed when Unsubscribe is called
+	Err() <-chan error
+	// Unsubscribe cancels the subscription and closes the error channel
+	Unsubscribe()
+}
+
+// Subscriber is implemented by transports that can hold eth_subscribe subscriptions
+type Subscriber interface {
+	// Subscribe calls eth_subscribe with args and delivers every notification on channel,
+	// which must be a writable channel of the notification type
+	Subscribe(ctx context.Context, channel any, args ...any) (Subscription, error)
+}
+
+// SubscribeNewHeads subscribes to new block headers
+func SubscribeNewHeads(ctx context.Context, s Subscriber, ch chan<- *types.Header) (Subscription, error) {
+	return s.Subscribe(ctx, ch, types.NewHeadsSubscription)
+}
+
+// SubscribeLogs subscribes to logs matching the filter, the block range of the filter is ignored by nodes
+func SubscribeLogs(ctx context.Context, s Subscriber, filter types.LogFilter, ch chan<- types.Log) (Subscription, error) {
+	return s.Subscribe(ctx, ch, types.LogsSubscription, filter)
+}
+
+// SubscribeNewPendingTransactions subscribes to hashes of transactions entering the pending pool
+func SubscribeNewPendingTransactions(ctx context.Context, s Subscriber, ch chan<- common.Hash) (Subscription, error) {
+	return s.Subscribe(ctx, ch, types.NewPendingTransactionsSubscription)
+}
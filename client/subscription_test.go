@@ -0,0 +1,402 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type testSubscriptionService struct{}
+
+func (s *testSubscriptionService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for i := int64(1); ; i++ {
+			select {
+			case <-sub.Err():
+				return
+			case <-time.After(10 * time.Millisecond):
+				header := &ethTypes.Header{Number: big.NewInt(i), Difficulty: big.NewInt(0)}
+				if err := notifier.Notify(sub.ID, header); err != nil {
+					return
+				}
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func newTestWebSocketServer(t *testing.T) (*rpc.Server, string) {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", new(testSubscriptionService)); err != nil {
+		t.Fatalf("register service failed: %v", err)
+	}
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return server, "ws" + strings.TrimPrefix(httpServer.URL, "http")
+}
+
+func TestWebSocketTransport_SubscribeNewHeads(t *testing.T) {
+	_, endpoint := newTestWebSocketServer(t)
+	transport, err := NewWebSocketTransport(endpoint)
+	if err != nil {
+		t.Fatalf("NewWebSocketTransport failed: %v", err)
+	}
+
+	heads := make(chan *types.Header)
+	sub, err := SubscribeNewHeads(context.Background(), transport, heads)
+	if err != nil {
+		t.Fatalf("SubscribeNewHeads failed: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	for want := int64(1); want <= 3; want++ {
+		select {
+		case head := <-heads:
+			if head.Number.Int64() != want {
+				t.Fatalf("expected head %d, got %d", want, head.Number.Int64())
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for head")
+		}
+	}
+}
+
+func TestClient_SubscribeFallback(t *testing.T) {
+	_, endpoint := newTestWebSocketServer(t)
+	ws, err := NewWebSocketTransport(endpoint)
+	if err != nil {
+		t.Fatalf("NewWebSocketTransport failed: %v", err)
+	}
+
+	cl, err := NewClient(WithTransport(&mockTransport{}))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if _, err := SubscribeNewPendingTransactions(context.Background(), cl, make(chan common.Hash)); !errors.Is(err, verrors.ErrSubscriptionNotSupported) {
+		t.Fatalf("expected ErrSubscriptionNotSupported, got %v", err)
+	}
+
+	cl, err = NewClient(WithTransport(&mockTransport{}, ws))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	heads := make(chan *types.Header, 1)
+	sub, err := cl.Subscribe(context.Background(), heads, types.NewHeadsSubscription)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer sub.Unsubscribe()
+	select {
+	case <-heads:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for head")
+	}
+}
+
+// switchHandler serves websocket connections with the current rpc server
+type switchHandler struct {
+	mu     sync.Mutex
+	server *rpc.Server
+}
+
+func (h *switchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	server := h.server
+	h.mu.Unlock()
+	server.WebsocketHandler([]string{"*"}).ServeHTTP(w, r)
+}
+
+// restart drops all connections of the current server and replaces it
+func (h *switchHandler) restart(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", new(testSubscriptionService)); err != nil {
+		t.Fatalf("register service failed: %v", err)
+	}
+	h.mu.Lock()
+	old := h.server
+	h.server = server
+	h.mu.Unlock()
+	if old != nil {
+		old.Stop()
+	}
+}
+
+func TestWebSocketTransport_ReconnectResubscribes(t *testing.T) {
+	handler := new(switchHandler)
+	handler.restart(t)
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+
+	transport, err := NewWebSocketTransport(
+		"ws"+strings.TrimPrefix(httpServer.URL, "http"),
+		WithReconnect(10*time.Millisecond, 100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewWebSocketTransport failed: %v", err)
+	}
+	defer transport.Close()
+
+	heads := make(chan *types.Header, 16)
+	sub, err := SubscribeNewHeads(context.Background(), transport, heads)
+	if err != nil {
+		t.Fatalf("SubscribeNewHeads failed: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	waitHead := func() {
+		select {
+		case <-heads:
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for head")
+		}
+	}
+	waitHead()
+
+	handler.restart(t)
+	select {
+	case event := <-transport.ReconnectEvents():
+		if event.Err != nil || event.Cause == nil {
+			t.Fatalf("unexpected reconnect event: %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reconnect")
+	}
+
+	// drain heads of the old connection, then expect heads of the resumed subscription
+	for len(heads) > 0 {
+		<-heads
+	}
+	waitHead()
+	if _, err := transport.Request(context.Background(), types.GetChainID); err != nil && isConnectionError(err) {
+		t.Fatalf("request after reconnect failed: %v", err)
+	}
+}
+
+// waitResumed waits for the first head of a new subscription, heads restart at 1 on every subscription
+func waitResumed(t *testing.T, heads chan *types.Header, sub Subscription) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case head := <-heads:
+			if head.Number.Int64() == 1 {
+				return
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-timeout:
+			t.Fatal("timed out waiting for the resumed subscription")
+		}
+	}
+}
+
+func TestWebSocketTransport_ReconnectAfterDrop(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", new(testSubscriptionService)); err != nil {
+		t.Fatalf("register service failed: %v", err)
+	}
+	defer server.Stop()
+	var (
+		mu    sync.Mutex
+		conns []net.Conn
+	)
+	httpServer := httptest.NewUnstartedServer(server.WebsocketHandler([]string{"*"}))
+	httpServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateHijacked {
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}
+	httpServer.Start()
+	defer httpServer.Close()
+
+	transport, err := NewWebSocketTransport("ws"+strings.TrimPrefix(httpServer.URL, "http"), WithReconnect(10*time.Millisecond, 100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWebSocketTransport failed: %v", err)
+	}
+	defer transport.Close()
+	heads := make(chan *types.Header, 16)
+	sub, err := SubscribeNewHeads(context.Background(), transport, heads)
+	if err != nil {
+		t.Fatalf("SubscribeNewHeads failed: %v", err)
+	}
+	defer sub.Unsubscribe()
+	waitResumed(t, heads, sub)
+
+	// drop the connection on the server side without closing the websocket
+	mu.Lock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	mu.Unlock()
+	select {
+	case event := <-transport.ReconnectEvents():
+		if event.Err != nil || event.Cause == nil {
+			t.Fatalf("unexpected reconnect event: %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reconnect")
+	}
+	waitResumed(t, heads, sub)
+}
+
+// stallingService answers eth_chainId only while it is not stalled, like a node that hangs without dropping
+// the connection
+type stallingService struct {
+	testSubscriptionService
+	stalled atomic.Bool
+}
+
+func (s *stallingService) ChainId(ctx context.Context) (string, error) {
+	if s.stalled.Load() {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	return "0x1", nil
+}
+
+func TestWebSocketTransport_KeepAliveReconnects(t *testing.T) {
+	service := new(stallingService)
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatalf("register service failed: %v", err)
+	}
+	defer server.Stop()
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpServer.Close()
+
+	transport, err := NewWebSocketTransport(
+		"ws"+strings.TrimPrefix(httpServer.URL, "http"),
+		WithReconnect(10*time.Millisecond, 100*time.Millisecond),
+		WithKeepAlive(20*time.Millisecond, 50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewWebSocketTransport failed: %v", err)
+	}
+	defer transport.Close()
+	// buffered for the heads arriving while nothing reads them
+	heads := make(chan *types.Header, 256)
+	sub, err := SubscribeNewHeads(context.Background(), transport, heads)
+	if err != nil {
+		t.Fatalf("SubscribeNewHeads failed: %v", err)
+	}
+	defer sub.Unsubscribe()
+	waitResumed(t, heads, sub)
+
+	// answered pings keep the connection
+	select {
+	case event := <-transport.ReconnectEvents():
+		t.Fatalf("unexpected reconnect while the node answers: %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	service.stalled.Store(true)
+	select {
+	case event := <-transport.ReconnectEvents():
+		if !errors.Is(event.Cause, context.DeadlineExceeded) {
+			t.Fatalf("expected the ping timeout as cause, got %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the keepalive to reconnect")
+	}
+	service.stalled.Store(false)
+	waitResumed(t, heads, sub)
+}
+
+func TestWebSocketTransport_SubscriptionErrorKeepsConnection(t *testing.T) {
+	_, endpoint := newTestWebSocketServer(t)
+	transport, err := NewWebSocketTransport(endpoint, WithReconnect(10*time.Millisecond, 100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWebSocketTransport failed: %v", err)
+	}
+	defer transport.Close()
+
+	heads := make(chan *types.Header, 256)
+	healthy, err := SubscribeNewHeads(context.Background(), transport, heads)
+	if err != nil {
+		t.Fatalf("SubscribeNewHeads failed: %v", err)
+	}
+	defer healthy.Unsubscribe()
+	// heads do not decode into numbers
+	broken, err := transport.Subscribe(context.Background(), make(chan int), types.NewHeadsSubscription)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	defer broken.Unsubscribe()
+
+	select {
+	case err := <-broken.Err():
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected the decoding error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the subscription error")
+	}
+	select {
+	case event := <-transport.ReconnectEvents():
+		t.Fatalf("a subscription error must not reconnect, got %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+	for len(heads) > 0 {
+		<-heads
+	}
+	select {
+	case <-heads:
+	case err := <-healthy.Err():
+		t.Fatalf("healthy subscription failed: %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the healthy subscription")
+	}
+}
+
+func TestWebSocketTransport_CloseWhileReconnecting(t *testing.T) {
+	handler := new(switchHandler)
+	handler.restart(t)
+	httpServer := httptest.NewServer(handler)
+	defer httpServer.Close()
+	transport, err := NewWebSocketTransport("ws"+strings.TrimPrefix(httpServer.URL, "http"), WithReconnect(10*time.Millisecond, 10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewWebSocketTransport failed: %v", err)
+	}
+	heads := make(chan *types.Header, 256)
+	if _, err := SubscribeNewHeads(context.Background(), transport, heads); err != nil {
+		t.Fatalf("SubscribeNewHeads failed: %v", err)
+	}
+
+	handler.restart(t)
+	transport.Close()
+	time.Sleep(100 * time.Millisecond)
+	if _, err := transport.Request(context.Background(), types.GetChainID); err == nil || !isConnectionError(err) {
+		t.Errorf("expected the closed transport to stay closed, got %v", err)
+	}
+}
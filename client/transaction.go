@@ -0,0 +1,299 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/transfer"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"strings"
+)
+
+// blobBaseFeeMultiplier leaves room for blob base fee increases
+const blobBaseFeeMultiplier = 2
+
+// PrepareTransactionRequest returns a copy of req with the missing fields filled from the node: the sender is the
+// account of the client, the chain ID comes from eth_chainId, the nonce is the pending transaction count, the fees
+// are estimated for the transaction type and the gas limit comes from eth_estimateGas. Without fields that decide
+// the type an EIP-1559 transaction is prepared, or a legacy transaction on chains without a base fee
+func (c *Client) PrepareTransactionRequest(ctx context.Context, req *types.TransactionRequest) (*types.TransactionRequest, error) {
+	prepared := *req
+	if prepared.From == (common.Address{}) {
+		if c.from == (common.Address{}) {
+			return nil, errors.New("account is required to prepare a transaction")
+		}
+		prepared.From = c.from
+	}
+	if prepared.ChainID == nil {
+		chainID, err := c.requestBigInt(ctx, types.GetChainID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get chain id: %w", err)
+		}
+		prepared.ChainID = chainID
+	}
+	if prepared.Nonce == nil {
+		nonce, err := c.pendingNonce(prepared.From)(ctx)
+		if err != nil {
+			return nil, err
+		}
+		prepared.Nonce = &nonce
+	}
+	if err := c.prepareFees(ctx, &prepared); err != nil {
+		return nil, err
+	}
+	if prepared.Gas == 0 {
+		res, err := c.Request(ctx, types.EstimateGas, prepared)
+		if err != nil {
+			return nil, fmt.Errorf("failed to estimate gas: %w", err)
+		}
+		if prepared.Gas, err = transfer.NewRPCResponseTransfer().TransferUint64(res); err != nil {
+			return nil, err
+		}
+	}
+	return &prepared, nil
+}
+
+// SendTransaction prepares the transaction, signs it with the account of the client and broadcasts it.
+// With a NonceManager transactions without nonce take theirs from the manager, which is resynchronized after every
+// failed broadcast. The nonce is only given back when the node rejected the transaction, after transport errors
+// the transaction may have reached the node and the resync tells whether the nonce was used.
+// Broadcast errors of the node wrap the errors package variables like errors.ErrNonceTooLow
+// method: eth_sendRawTransaction
+func (c *Client) SendTransaction(ctx context.Context, req *types.TransactionRequest) (common.Hash, error) {
+	if c.account == nil {
+		return common.Hash{}, errAccountRequired
+	}
+	if req.From != (common.Address{}) && req.From != c.from {
+		return common.Hash{}, fmt.Errorf("cannot sign for %s with the account %s", req.From, c.from)
+	}
+	if req.Nonce != nil || c.nonceManager == nil {
+		signedTx, err := c.signTransaction(ctx, req)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return c.sendSignedTransaction(ctx, signedTx)
+	}
+
+	managed := *req
+	if managed.ChainID == nil {
+		chainID, err := c.requestBigInt(ctx, types.GetChainID)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get chain id: %w", err)
+		}
+		managed.ChainID = chainID
+	}
+	nonce, err := c.nonceManager.Consume(ctx, managed.ChainID, c.from, c.pendingNonce(c.from))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	managed.Nonce = &nonce
+	signedTx, err := c.signTransaction(ctx, &managed)
+	if err != nil {
+		c.nonceManager.Release(managed.ChainID, c.from, nonce)
+		return common.Hash{}, err
+	}
+	txHash, err := c.sendSignedTransaction(ctx, signedTx)
+	if err != nil {
+		switch {
+		case errors.Is(err, verrors.ErrNonceTooHigh):
+			c.nonceManager.Release(managed.ChainID, c.from, nonce)
+			c.nonceManager.Reset(managed.ChainID, c.from)
+		case errors.Is(err, verrors.ErrNonceTooLow), errors.Is(err, verrors.ErrInvalidNonce):
+			c.nonceManager.Sent(managed.ChainID, c.from, nonce)
+			c.nonceManager.resync(managed.ChainID, c.from)
+		case errors.Is(err, verrors.ErrTransactionRejected):
+			c.nonceManager.Release(managed.ChainID, c.from, nonce)
+			c.nonceManager.resync(managed.ChainID, c.from)
+		default:
+			c.nonceManager.Sent(managed.ChainID, c.from, nonce)
+			c.nonceManager.resync(managed.ChainID, c.from)
+		}
+		return common.Hash{}, err
+	}
+	c.nonceManager.Sent(managed.ChainID, c.from, nonce)
+	return txHash, nil
+}
+
+// signTransaction prepares and signs a transaction
+func (c *Client) signTransaction(ctx context.Context, req *types.TransactionRequest) (*ethTypes.Transaction, error) {
+	prepared, err := c.PrepareTransactionRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	txData, err := prepared.TxData()
+	if err != nil {
+		return nil, err
+	}
+	return c.account.SignTransaction(ctx, ethTypes.NewTx(txData), prepared.ChainID)
+}
+
+// sendSignedTransaction broadcasts a signed transaction
+// method: eth_sendRawTransaction
+func (c *Client) sendSignedTransaction(ctx context.Context, signedTx *ethTypes.Transaction) (common.Hash, error) {
+	var txHash common.Hash
+	if err := c.SendRawTransaction(ctx, signedTx, &txHash); err != nil {
+		return common.Hash{}, classifyBroadcastError(err)
+	}
+	return txHash, nil
+}
+
+// broadcastError is an answer of the node to a broadcast. It unwraps to the error of the node and to the errors
+// package variables describing it
+type broadcastError struct {
+	err   error
+	kinds []error
+}
+
+func (e *broadcastError) Error() string {
+	return e.err.Error()
+}
+
+func (e *broadcastError) Unwrap() []error {
+	return append([]error{e.err}, e.kinds...)
+}
+
+// broadcastErrorKinds are the mempool errors a broadcast can fail with, their messages are the ones of geth's
+// transaction pool, which other clients follow
+var broadcastErrorKinds = []error{
+	verrors.ErrNonceTooLow,
+	verrors.ErrNonceTooHigh,
+	verrors.ErrInvalidNonce,
+	verrors.ErrTransactionKnown,
+	verrors.ErrTransactionUnderpriced,
+}
+
+// classifyBroadcastError wraps an error the node answered a broadcast with in a broadcastError. Nodes report
+// mempool errors with the generic codes -32000 or -32003 (transaction rejected), so the kind is taken from the
+// message. Known and underpriced transactions mean a transaction with the nonce is in the mempool, every other
+// answer is wrapped with errors.ErrTransactionRejected. Transport errors are returned unchanged
+func classifyBroadcastError(err error) error {
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return err
+	}
+	msg := strings.ToLower(rpcErr.Error())
+	for _, kind := range broadcastErrorKinds {
+		if !strings.Contains(msg, kind.Error()) {
+			continue
+		}
+		if kind == verrors.ErrTransactionKnown || kind == verrors.ErrTransactionUnderpriced {
+			return &broadcastError{err: err, kinds: []error{kind}}
+		}
+		return &broadcastError{err: err, kinds: []error{kind, verrors.ErrTransactionRejected}}
+	}
+	return &broadcastError{err: err, kinds: []error{verrors.ErrTransactionRejected}}
+}
+
+// pendingNonce returns a function that fetches the pending transaction count of address, the next free nonce
+// method: eth_getTransactionCount
+func (c *Client) pendingNonce(address common.Address) func(ctx context.Context) (uint64, error) {
+	return func(ctx context.Context) (uint64, error) {
+		res, err := c.Request(ctx, types.GetTransactionCount, address.Hex(), types.PENDING)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get nonce: %w", err)
+		}
+		return transfer.NewRPCResponseTransfer().TransferUint64(res)
+	}
+}
+
+// prepareFees decides the transaction type and fills the missing fees
+func (c *Client) prepareFees(ctx context.Context, req *types.TransactionRequest) error {
+	var baseFee *big.Int
+	txType, ok := req.InferType()
+	if !ok || req.MaxFeePerGas == nil && txType != ethTypes.LegacyTxType && txType != ethTypes.AccessListTxType {
+		res, err := c.Request(ctx, types.GetBlockByNumber, types.LATEST, false)
+		if err != nil {
+			return fmt.Errorf("failed to get latest block: %w", err)
+		}
+		block, err := transfer.NewRPCResponseTransfer().TransferBlock(res)
+		if err != nil {
+			return err
+		}
+		baseFee = block.BaseFeePerGas
+	}
+	if !ok {
+		txType = ethTypes.DynamicFeeTxType
+		if baseFee == nil {
+			txType = ethTypes.LegacyTxType
+		}
+		req.Type = &txType
+	}
+
+	if txType == ethTypes.LegacyTxType || txType == ethTypes.AccessListTxType {
+		if req.GasPrice == nil {
+			gasPrice, err := c.requestBigInt(ctx, types.GasPrice)
+			if err != nil {
+				return fmt.Errorf("failed to get gas price: %w", err)
+			}
+			req.GasPrice = util.MultiplyFee(gasPrice, util.DefaultBaseFeeMultiplier)
+		}
+		return nil
+	}
+
+	if req.MaxPriorityFeePerGas == nil {
+		priorityFee, err := c.maxPriorityFeePerGas(ctx)
+		if err != nil {
+			return err
+		}
+		if req.MaxFeePerGas != nil && req.MaxFeePerGas.Cmp(priorityFee) < 0 {
+			priorityFee = req.MaxFeePerGas
+		}
+		req.MaxPriorityFeePerGas = priorityFee
+	}
+	if req.MaxFeePerGas == nil {
+		if baseFee == nil {
+			return verrors.ErrEIP1559NotSupported
+		}
+		maxFee := util.MultiplyFee(baseFee, util.DefaultBaseFeeMultiplier)
+		req.MaxFeePerGas = maxFee.Add(maxFee, req.MaxPriorityFeePerGas)
+	}
+	if req.MaxFeePerGas.Cmp(req.MaxPriorityFeePerGas) < 0 {
+		return fmt.Errorf("max fee per gas %v is lower than max priority fee per gas %v", req.MaxFeePerGas, req.MaxPriorityFeePerGas)
+	}
+
+	if txType == ethTypes.BlobTxType && req.MaxFeePerBlobGas == nil {
+		blobBaseFee, err := c.requestBigInt(ctx, types.BlobBaseFee)
+		if err != nil {
+			return fmt.Errorf("failed to get blob base fee: %w", err)
+		}
+		req.MaxFeePerBlobGas = blobBaseFee.Mul(blobBaseFee, big.NewInt(blobBaseFeeMultiplier))
+	}
+	return nil
+}
+
+// maxPriorityFeePerGas gets the priority fee like eth's GetMaxPriorityFeePerGas, which client cannot import
+func (c *Client) maxPriorityFeePerGas(ctx context.Context) (*big.Int, error) {
+	priorityFee, err := c.requestBigInt(ctx, types.MaxPriorityFeePerGas)
+	if err == nil {
+		return priorityFee, nil
+	}
+	var rpcErr rpc.Error
+	if !errors.As(err, &rpcErr) {
+		return nil, fmt.Errorf("failed to get max priority fee per gas: %w", err)
+	}
+	res, err := c.Request(ctx, types.FeeHistory, hexutil.EncodeUint64(util.PriorityFeeHistoryBlocks), types.LATEST, []float64{util.PriorityFeePercentile})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate priority fee from fee history: %w", err)
+	}
+	var history types.FeeHistoryResult
+	if err := transfer.NewRPCResponseTransfer().TransferStruct(res, &history); err != nil {
+		return nil, err
+	}
+	return util.MedianPriorityFee(&history), nil
+}
+
+// requestBigInt requests a method that returns a hex quantity
+func (c *Client) requestBigInt(ctx context.Context, method types.RPCMethod, params ...any) (*big.Int, error) {
+	res, err := c.Request(ctx, method, params...)
+	if err != nil {
+		return nil, err
+	}
+	return transfer.NewRPCResponseTransfer().TransferBigInt(res)
+}
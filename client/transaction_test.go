@@ -0,0 +1,206 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/wallet"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testPrivateKey = "4c0883a69102937d6231471b5dbb6204fe512961708279a0d02c0b9a0d3d8a27"
+
+// newNodeTransport mocks a node on chain 1 with the given latest base fee, a nil base fee mocks a legacy chain.
+// Raw transactions are decoded into sent
+func newNodeTransport(t *testing.T, baseFee *big.Int, sent **ethTypes.Transaction) *mockTransport {
+	return &mockTransport{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			switch method {
+			case types.GetChainID:
+				return json.RawMessage(`"0x1"`), nil
+			case types.GetTransactionCount:
+				if params[1] != types.PENDING {
+					return nil, fmt.Errorf("unexpected block tag: %v", params[1])
+				}
+				return json.RawMessage(`"0x7"`), nil
+			case types.GetBlockByNumber:
+				if baseFee == nil {
+					return json.RawMessage(`{"number": "0x10"}`), nil
+				}
+				return json.Marshal(map[string]any{"number": "0x10", "baseFeePerGas": (*hexutil.Big)(baseFee)})
+			case types.MaxPriorityFeePerGas:
+				return json.RawMessage(`"0x3b9aca00"`), nil
+			case types.GasPrice:
+				return json.RawMessage(`"0x4a817c800"`), nil
+			case types.BlobBaseFee:
+				return json.RawMessage(`"0x10"`), nil
+			case types.EstimateGas:
+				if _, ok := params[0].(types.TransactionRequest); !ok {
+					return nil, fmt.Errorf("unexpected estimate params: %v", params)
+				}
+				return json.RawMessage(`"0x5208"`), nil
+			case types.SendRawTransaction:
+				tx := new(ethTypes.Transaction)
+				if err := tx.UnmarshalBinary(hexutil.MustDecode(params[0].(string))); err != nil {
+					t.Errorf("failed to decode raw transaction: %v", err)
+				}
+				*sent = tx
+				return json.Marshal(tx.Hash())
+			}
+			return nil, fmt.Errorf("unexpected method: %s", method)
+		},
+	}
+}
+
+func TestPrepareTransactionRequest(t *testing.T) {
+	to := common.HexToAddress("0x1234")
+	cl, err := NewClient(WithTransport(newNodeTransport(t, big.NewInt(10e9), nil)), WithPrivateKey(testPrivateKey))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	prepared, err := cl.PrepareTransactionRequest(context.Background(), &types.TransactionRequest{To: &to, Value: big.NewInt(1)})
+	if err != nil {
+		t.Fatalf("PrepareTransactionRequest failed: %v", err)
+	}
+	if prepared.From != cl.from || prepared.ChainID.Int64() != 1 || *prepared.Nonce != 7 || prepared.Gas != 21000 {
+		t.Errorf("unexpected prepared request: %+v", prepared)
+	}
+	if *prepared.Type != ethTypes.DynamicFeeTxType || prepared.GasPrice != nil {
+		t.Errorf("expected EIP-1559 transaction, got type %d", *prepared.Type)
+	}
+	// 10 gwei * 1.2 + 1 gwei
+	if prepared.MaxFeePerGas.Int64() != 13e9 || prepared.MaxPriorityFeePerGas.Int64() != 1e9 {
+		t.Errorf("unexpected fees: %v %v", prepared.MaxFeePerGas, prepared.MaxPriorityFeePerGas)
+	}
+
+	blobReq := &types.TransactionRequest{To: &to, BlobVersionedHashes: []common.Hash{{0x01}}, Gas: 100000}
+	prepared, err = cl.PrepareTransactionRequest(context.Background(), blobReq)
+	if err != nil {
+		t.Fatalf("PrepareTransactionRequest failed: %v", err)
+	}
+	if txType, _ := prepared.InferType(); txType != ethTypes.BlobTxType || prepared.MaxFeePerBlobGas.Int64() != 32 {
+		t.Errorf("unexpected blob transaction: type %d, blob fee %v", txType, prepared.MaxFeePerBlobGas)
+	}
+	if blobReq.MaxFeePerBlobGas != nil {
+		t.Error("the request passed in must not be modified")
+	}
+
+	legacy, err := NewClient(WithTransport(newNodeTransport(t, nil, nil)), WithPrivateKey(testPrivateKey))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	prepared, err = legacy.PrepareTransactionRequest(context.Background(), &types.TransactionRequest{To: &to})
+	if err != nil {
+		t.Fatalf("PrepareTransactionRequest failed: %v", err)
+	}
+	// 20 gwei * 1.2
+	if *prepared.Type != ethTypes.LegacyTxType || prepared.GasPrice.Int64() != 24e9 || prepared.MaxFeePerGas != nil {
+		t.Errorf("expected legacy transaction, got type %d, gas price %v", *prepared.Type, prepared.GasPrice)
+	}
+}
+
+func TestInferTransactionType(t *testing.T) {
+	eip2930 := uint8(ethTypes.AccessListTxType)
+	tests := []struct {
+		req  types.TransactionRequest
+		want uint8
+	}{
+		{types.TransactionRequest{GasPrice: big.NewInt(1)}, ethTypes.LegacyTxType},
+		{types.TransactionRequest{GasPrice: big.NewInt(1), AccessList: ethTypes.AccessList{}}, ethTypes.AccessListTxType},
+		{types.TransactionRequest{MaxPriorityFeePerGas: big.NewInt(1)}, ethTypes.DynamicFeeTxType},
+		{types.TransactionRequest{MaxFeePerBlobGas: big.NewInt(1)}, ethTypes.BlobTxType},
+		{types.TransactionRequest{AuthorizationList: []ethTypes.SetCodeAuthorization{{}}}, ethTypes.SetCodeTxType},
+		{types.TransactionRequest{Type: &eip2930, MaxFeePerGas: big.NewInt(1)}, ethTypes.AccessListTxType},
+	}
+	for i, tt := range tests {
+		if got, ok := tt.req.InferType(); !ok || got != tt.want {
+			t.Errorf("case %d: expected type %d, got %d", i, tt.want, got)
+		}
+	}
+	if _, ok := (&types.TransactionRequest{}).InferType(); ok {
+		t.Error("expected unknown type for an empty request")
+	}
+}
+
+func TestSendTransaction(t *testing.T) {
+	var sent *ethTypes.Transaction
+	cl, err := NewClient(WithTransport(newNodeTransport(t, big.NewInt(10e9), &sent)), WithPrivateKey(testPrivateKey))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	to := common.HexToAddress("0x1234")
+	hash, err := cl.SendTransaction(context.Background(), &types.TransactionRequest{To: &to, Value: big.NewInt(1e18), Data: []byte{0x01}})
+	if err != nil {
+		t.Fatalf("SendTransaction failed: %v", err)
+	}
+	if sent == nil || sent.Hash() != hash {
+		t.Fatalf("expected transaction %s to be sent", hash)
+	}
+	sender, err := ethTypes.Sender(ethTypes.LatestSignerForChainID(big.NewInt(1)), sent)
+	if err != nil || sender != cl.from {
+		t.Errorf("expected sender %s, got %s (%v)", cl.from, sender, err)
+	}
+	if sent.Type() != ethTypes.DynamicFeeTxType || sent.Nonce() != 7 || sent.Gas() != 21000 || sent.GasFeeCap().Int64() != 13e9 {
+		t.Errorf("unexpected transaction: type %d, nonce %d, gas %d, fee cap %v", sent.Type(), sent.Nonce(), sent.Gas(), sent.GasFeeCap())
+	}
+
+	other := common.HexToAddress("0x1")
+	if _, err := cl.SendTransaction(context.Background(), &types.TransactionRequest{From: other, To: &to}); err == nil {
+		t.Error("expected error when sending from another account")
+	}
+}
+
+// countingAccount stands in for a remote signer
+type countingAccount struct {
+	wallet.Account
+	signed int
+}
+
+func (a *countingAccount) SignTransaction(ctx context.Context, tx *ethTypes.Transaction, chainID *big.Int) (*ethTypes.Transaction, error) {
+	a.signed++
+	return a.Account.SignTransaction(ctx, tx, chainID)
+}
+
+func TestSendTransaction_WithAccount(t *testing.T) {
+	local, _ := wallet.PrivateKeyAccountFromHex(testPrivateKey)
+	account := &countingAccount{Account: local}
+	var sent *ethTypes.Transaction
+	cl, err := NewClient(WithTransport(newNodeTransport(t, big.NewInt(10e9), &sent)), WithAccount(account))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	to := common.HexToAddress("0x1234")
+	if _, err := cl.SendTransaction(context.Background(), &types.TransactionRequest{To: &to}); err != nil {
+		t.Fatalf("SendTransaction failed: %v", err)
+	}
+	if account.signed != 1 || sent == nil {
+		t.Fatalf("expected the account to sign the sent transaction, signed %d", account.signed)
+	}
+	if sender, _ := ethTypes.Sender(ethTypes.LatestSignerForChainID(big.NewInt(1)), sent); sender != local.Address() {
+		t.Errorf("expected sender %s, got %s", local.Address(), sender)
+	}
+}
+
+func TestSignMessage_RecoveryID(t *testing.T) {
+	cl, err := NewClient(WithTransport(&mockTransport{}), WithPrivateKey(testPrivateKey))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	signature, err := cl.SignMessage([]byte("hello world"))
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte("hello world")), signature)
+	if err != nil || crypto.PubkeyToAddress(*pub) != cl.from {
+		t.Errorf("expected a signature with v 0 or 1 recovering to %s, got %x (%v)", cl.from, signature, err)
+	}
+}
@@ -31,3 +31,8 @@ func (t *HTTPTransport) Request(ctx context.Context, method types.RPCMethod, par
 	err := t.client.CallContext(ctx, &result, string(method), params...)
 	return result, err
 }
+
+// BatchRequest implements the Transport interface's BatchRequest method
+func (t *HTTPTransport) BatchRequest(ctx context.Context, batch []BatchElem) error {
+	return rpcBatchCall(ctx, t.client, batch)
+}
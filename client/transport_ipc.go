@@ -27,3 +27,13 @@ func (t *IPCTransport) Request(ctx context.Context, method types.RPCMethod, para
 	err := t.client.CallContext(ctx, &result, string(method), params...)
 	return result, err
 }
+
+// BatchRequest implements the Transport interface's BatchRequest method
+func (t *IPCTransport) BatchRequest(ctx context.Context, batch []BatchElem) error {
+	return rpcBatchCall(ctx, t.client, batch)
+}
+
+// Subscribe implements the Subscriber interface's Subscribe method
+func (t *IPCTransport) Subscribe(ctx context.Context, channel any, args ...any) (Subscription, error) {
+	return t.client.EthSubscribe(ctx, channel, args...)
+}
@@ -3,27 +3,462 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"github.com/AutoArbi/go-viem/types"
 	"github.com/ethereum/go-ethereum/rpc"
+	"sync"
+	"time"
 )
 
+const (
+	defaultMinReconnectBackoff = 500 * time.Millisecond
+	defaultMaxReconnectBackoff = 30 * time.Second
+	defaultKeepAliveTimeout    = 10 * time.Second
+	reconnectEventBuffer       = 16
+)
+
+// WebSocketOption config function type for WebSocketTransport
+type WebSocketOption func(*webSocketConfig) error
+
+type webSocketConfig struct {
+	reconnect            bool
+	minBackoff           time.Duration
+	maxBackoff           time.Duration
+	maxReconnectAttempts int
+	keepAliveInterval    time.Duration
+	keepAliveTimeout     time.Duration
+}
+
+// ReconnectEvent is emitted every time the transport lost its connection and tried to re-establish it.
+// Consumers can use it to backfill heads or logs missed between DisconnectedAt and ReconnectedAt
+type ReconnectEvent struct {
+	// Cause is the error that closed the previous connection
+	Cause error
+	// Err is set when reconnecting gave up after the max attempts, active subscriptions failed with it
+	Err error
+	// Attempts is the number of dial attempts
+	Attempts       int
+	DisconnectedAt time.Time
+	ReconnectedAt  time.Time
+}
+
 // WebSocketTransport struct
 type WebSocketTransport struct {
+	endpoint string
+	cfg      webSocketConfig
+
+	mu     sync.RWMutex
+	client *rpc.Client
+	subs   map[*wsSubscription]struct{}
+
+	lost      chan lostConnection
+	events    chan ReconnectEvent
+	quit      chan struct{}
+	closeOnce sync.Once
+}
+
+// lostConnection reports a failure of the connection client
+type lostConnection struct {
 	client *rpc.Client
+	err    error
 }
 
 // NewWebSocketTransport create a new WebSocketTransport instance
-func NewWebSocketTransport(endpoint string) (*WebSocketTransport, error) {
+func NewWebSocketTransport(endpoint string, opts ...WebSocketOption) (*WebSocketTransport, error) {
+	cfg := webSocketConfig{
+		minBackoff:       defaultMinReconnectBackoff,
+		maxBackoff:       defaultMaxReconnectBackoff,
+		keepAliveTimeout: defaultKeepAliveTimeout,
+	}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return nil, err
+		}
+	}
+
 	c, err := rpc.DialWebsocket(context.Background(), endpoint, "")
 	if err != nil {
 		return nil, err
 	}
-	return &WebSocketTransport{client: c}, nil
+	t := &WebSocketTransport{
+		endpoint: endpoint,
+		cfg:      cfg,
+		client:   c,
+		subs:     make(map[*wsSubscription]struct{}),
+		lost:     make(chan lostConnection, 1),
+		events:   make(chan ReconnectEvent, reconnectEventBuffer),
+		quit:     make(chan struct{}),
+	}
+	if cfg.reconnect {
+		go t.loop()
+	}
+	return t, nil
+}
+
+// WithReconnect enables reconnecting after the connection dropped, waiting between dial
+// attempts with an exponential backoff from minBackoff up to maxBackoff
+func WithReconnect(minBackoff, maxBackoff time.Duration) WebSocketOption {
+	return func(c *webSocketConfig) error {
+		if minBackoff <= 0 || maxBackoff < minBackoff {
+			return errors.New("invalid reconnect backoff")
+		}
+		c.reconnect = true
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+		return nil
+	}
+}
+
+// WithMaxReconnectAttempts limits the dial attempts per reconnect, 0 retries forever
+func WithMaxReconnectAttempts(n int) WebSocketOption {
+	return func(c *webSocketConfig) error {
+		if n < 0 {
+			return errors.New("max reconnect attempts must be >= 0")
+		}
+		c.maxReconnectAttempts = n
+		return nil
+	}
+}
+
+// WithKeepAlive pings the node every interval and reconnects when no answer arrived within timeout.
+// It only takes effect together with WithReconnect
+func WithKeepAlive(interval, timeout time.Duration) WebSocketOption {
+	return func(c *webSocketConfig) error {
+		if interval <= 0 || timeout <= 0 {
+			return errors.New("keepalive interval and timeout must be positive")
+		}
+		c.keepAliveInterval = interval
+		c.keepAliveTimeout = timeout
+		return nil
+	}
 }
 
 // Request implements the Transport interface's Request method
 func (t *WebSocketTransport) Request(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+	c := t.current()
 	var result json.RawMessage
-	err := t.client.CallContext(ctx, &result, string(method), params...)
+	err := c.CallContext(ctx, &result, string(method), params...)
+	if err != nil && isConnectionError(err) {
+		t.connectionLost(c, err)
+	}
 	return result, err
 }
+
+// BatchRequest implements the Transport interface's BatchRequest method
+func (t *WebSocketTransport) BatchRequest(ctx context.Context, batch []BatchElem) error {
+	c := t.current()
+	err := rpcBatchCall(ctx, c, batch)
+	if err != nil && isConnectionError(err) {
+		t.connectionLost(c, err)
+	}
+	return err
+}
+
+// Subscribe implements the Subscriber interface's Subscribe method.
+// With reconnect enabled the subscription is re-established on the new connection and keeps delivering on channel
+func (t *WebSocketTransport) Subscribe(ctx context.Context, channel any, args ...any) (Subscription, error) {
+	c := t.current()
+	if !t.cfg.reconnect {
+		return c.EthSubscribe(ctx, channel, args...)
+	}
+
+	sub, err := c.EthSubscribe(ctx, channel, args...)
+	if err != nil {
+		if isConnectionError(err) {
+			t.connectionLost(c, err)
+		}
+		return nil, err
+	}
+	s := &wsSubscription{
+		transport: t,
+		channel:   channel,
+		args:      args,
+		err:       make(chan error, 1),
+		quit:      make(chan struct{}),
+	}
+	s.attach(c, sub)
+	t.mu.Lock()
+	t.subs[s] = struct{}{}
+	current := t.client
+	t.mu.Unlock()
+	// a reconnect that replaced c before s was registered did not resubscribe it
+	if current != c {
+		sub.Unsubscribe()
+		s.resubscribe(ctx, current)
+	}
+	return s, nil
+}
+
+// ReconnectEvents returns the channel reconnect events are delivered on.
+// Events are dropped when the channel buffer is full
+func (t *WebSocketTransport) ReconnectEvents() <-chan ReconnectEvent {
+	return t.events
+}
+
+// Close closes the connection and stops reconnecting
+func (t *WebSocketTransport) Close() {
+	t.closeOnce.Do(func() {
+		t.mu.Lock()
+		close(t.quit)
+		c := t.client
+		t.mu.Unlock()
+		c.Close()
+	})
+}
+
+func (t *WebSocketTransport) current() *rpc.Client {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.client
+}
+
+// connectionLost wakes up the reconnect loop unless a reconnect is already pending
+func (t *WebSocketTransport) connectionLost(c *rpc.Client, err error) {
+	if !t.cfg.reconnect {
+		return
+	}
+	select {
+	case t.lost <- lostConnection{client: c, err: err}:
+	default:
+	}
+}
+
+// loop reconnects on lost connections and sends keepalive pings
+func (t *WebSocketTransport) loop() {
+	var keepAlive <-chan time.Time
+	if t.cfg.keepAliveInterval > 0 {
+		ticker := time.NewTicker(t.cfg.keepAliveInterval)
+		defer ticker.Stop()
+		keepAlive = ticker.C
+	}
+
+	for {
+		select {
+		case <-t.quit:
+			return
+		case lost := <-t.lost:
+			if lost.client == t.current() {
+				t.reconnect(lost.err)
+			}
+		case <-keepAlive:
+			if err := t.ping(); err != nil {
+				t.reconnect(err)
+			}
+		}
+	}
+}
+
+// ping checks the connection with a cheap request
+func (t *WebSocketTransport) ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), t.cfg.keepAliveTimeout)
+	defer cancel()
+	var result json.RawMessage
+	err := t.current().CallContext(ctx, &result, string(types.GetChainID))
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return nil
+	}
+	return err
+}
+
+// reconnect dials until a new connection is established and re-subscribes all active subscriptions
+func (t *WebSocketTransport) reconnect(cause error) {
+	event := ReconnectEvent{Cause: cause, DisconnectedAt: time.Now()}
+	t.current().Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-t.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	backoff := t.cfg.minBackoff
+	for {
+		event.Attempts++
+		c, err := rpc.DialWebsocket(ctx, t.endpoint, "")
+		if err == nil {
+			t.mu.Lock()
+			select {
+			case <-t.quit:
+				// Close ran while dialing, it closed the previous client only
+				t.mu.Unlock()
+				c.Close()
+				return
+			default:
+			}
+			t.client = c
+			subs := make([]*wsSubscription, 0, len(t.subs))
+			for s := range t.subs {
+				subs = append(subs, s)
+			}
+			t.mu.Unlock()
+
+			for _, s := range subs {
+				s.resubscribe(ctx, c)
+			}
+			event.ReconnectedAt = time.Now()
+			t.emit(event)
+			return
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		if t.cfg.maxReconnectAttempts > 0 && event.Attempts >= t.cfg.maxReconnectAttempts {
+			event.Err = err
+			t.mu.Lock()
+			subs := t.subs
+			t.subs = make(map[*wsSubscription]struct{})
+			t.mu.Unlock()
+			for s := range subs {
+				s.fail(err)
+			}
+			t.emit(event)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > t.cfg.maxBackoff {
+			backoff = t.cfg.maxBackoff
+		}
+	}
+}
+
+func (t *WebSocketTransport) emit(event ReconnectEvent) {
+	select {
+	case t.events <- event:
+	default:
+	}
+}
+
+func (t *WebSocketTransport) removeSubscription(s *wsSubscription) {
+	t.mu.Lock()
+	delete(t.subs, s)
+	t.mu.Unlock()
+}
+
+// isConnectionError reports whether err was caused by the connection rather than by the node answering with an error,
+// by a result that does not decode into the expected type or by a subscriber that does not keep up
+func isConnectionError(err error) bool {
+	var (
+		rpcErr       rpc.Error
+		typeErr      *json.UnmarshalTypeError
+		syntaxErr    *json.SyntaxError
+		unmarshalErr *json.InvalidUnmarshalError
+	)
+	switch {
+	case errors.As(err, &rpcErr),
+		errors.As(err, &typeErr),
+		errors.As(err, &syntaxErr),
+		errors.As(err, &unmarshalErr),
+		errors.Is(err, rpc.ErrNoResult),
+		errors.Is(err, rpc.ErrSubscriptionQueueOverflow),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+	return true
+}
+
+// wsSubscription is a subscription that survives reconnects of its WebSocketTransport
+type wsSubscription struct {
+	transport *WebSocketTransport
+	channel   any
+	args      []any
+
+	mu     sync.Mutex
+	sub    *rpc.ClientSubscription
+	closed bool
+	err    chan error
+	quit   chan struct{}
+	once   sync.Once
+}
+
+// Err implements the Subscription interface's Err method
+func (s *wsSubscription) Err() <-chan error {
+	return s.err
+}
+
+// Unsubscribe implements the Subscription interface's Unsubscribe method
+func (s *wsSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		close(s.quit)
+		s.transport.removeSubscription(s)
+
+		s.mu.Lock()
+		sub := s.sub
+		s.closed = true
+		close(s.err)
+		s.mu.Unlock()
+
+		if sub != nil {
+			sub.Unsubscribe()
+		}
+	})
+}
+
+// attach makes sub the active underlying subscription and watches it for failures. Connection failures reconnect
+// the transport, other failures end the subscription with the error
+func (s *wsSubscription) attach(c *rpc.Client, sub *rpc.ClientSubscription) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		sub.Unsubscribe()
+		return
+	}
+	s.sub = sub
+	s.mu.Unlock()
+
+	go func() {
+		select {
+		case err, ok := <-sub.Err():
+			switch {
+			case !ok || err == nil:
+				// the client was closed by a reconnect or Close
+			case isConnectionError(err):
+				select {
+				case s.transport.lost <- lostConnection{client: c, err: err}:
+				case <-s.transport.quit:
+				}
+			default:
+				// the subscription itself failed, the connection and other subscriptions are fine
+				s.transport.removeSubscription(s)
+				s.fail(err)
+			}
+		case <-s.quit:
+		}
+	}()
+}
+
+// resubscribe re-establishes the subscription on the new connection
+func (s *wsSubscription) resubscribe(ctx context.Context, c *rpc.Client) {
+	sub, err := c.EthSubscribe(ctx, s.channel, s.args...)
+	if err != nil {
+		s.transport.removeSubscription(s)
+		s.fail(err)
+		return
+	}
+	s.attach(c, sub)
+}
+
+// fail delivers err to the subscriber unless it already unsubscribed
+func (s *wsSubscription) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.err <- err:
+	default:
+	}
+}
@@ -0,0 +1,8 @@
+package errors
+
+import "errors"
+
+var (
+	// ErrEventNotFound is returned when no event of an ABI matches a log or an event name
+	ErrEventNotFound = errors.New("no matching event in abi")
+)
@@ -3,6 +3,24 @@ package errors
 import "errors"
 
 var (
-	ErrTimeout = errors.New("request timeout")
-	ErrNetwork = errors.New("network unreachable")
+	ErrTimeout                  = errors.New("request timeout")
+	ErrNetwork                  = errors.New("network unreachable")
+	ErrSubscriptionNotSupported = errors.New("subscriptions not supported by transport")
+	// ErrTransactionDropped is returned when a transaction's nonce was consumed without a replacement being found
+	ErrTransactionDropped = errors.New("transaction dropped")
+	// ErrEIP1559NotSupported is returned when EIP-1559 fees are estimated on a chain whose blocks have no base fee
+	ErrEIP1559NotSupported = errors.New("chain does not support EIP-1559 fees")
+
+	// ErrTransactionRejected is wrapped by broadcast errors when the node answered and did not accept the transaction
+	ErrTransactionRejected = errors.New("transaction rejected")
+	// ErrNonceTooLow is wrapped by broadcast errors when the nonce of the transaction was already used
+	ErrNonceTooLow = errors.New("nonce too low")
+	// ErrNonceTooHigh is wrapped by broadcast errors when the nonce of the transaction leaves a gap
+	ErrNonceTooHigh = errors.New("nonce too high")
+	// ErrInvalidNonce is wrapped by broadcast errors when the node rejected the nonce without telling why
+	ErrInvalidNonce = errors.New("invalid nonce")
+	// ErrTransactionKnown is wrapped by broadcast errors when the node already has the transaction in its mempool
+	ErrTransactionKnown = errors.New("already known")
+	// ErrTransactionUnderpriced is wrapped by broadcast errors when the fees are too low to enter or replace in the mempool
+	ErrTransactionUnderpriced = errors.New("transaction underpriced")
 )
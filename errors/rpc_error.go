@@ -5,4 +5,6 @@ import "errors"
 var (
 	ErrInvalidRequest = errors.New("invalid request")
 	ErrInternalError  = errors.New("internal error")
+	// ErrNotFound is returned when the node answered null for a block, transaction or receipt
+	ErrNotFound = errors.New("not found")
 )
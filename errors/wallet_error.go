@@ -0,0 +1,10 @@
+package errors
+
+import "errors"
+
+var (
+	// ErrKeystorePassword is returned when a keystore cannot be decrypted with the given password
+	ErrKeystorePassword = errors.New("could not decrypt keystore with given password")
+	// ErrInvalidSignature is returned when a signature is malformed or no public key can be recovered from it
+	ErrInvalidSignature = errors.New("invalid signature")
+)
@@ -3,22 +3,23 @@ package eth
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"math/big"
 	"reflect"
 	"testing"
 
+	"github.com/AutoArbi/go-viem/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 func TestGetBalance(t *testing.T) {
 	// "0xDE0B6B3A7640000" 表示 1 ETH (1e18 wei)
 	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method == "getBalance" {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method == types.GetBalance {
 				return json.RawMessage("\"0xDE0B6B3A7640000\""), nil
 			}
-			return nil, errors.New("unexpected method: " + method)
+			return nil, fmt.Errorf("unexpected method: %s", method)
 		},
 	}
 	pc := &Client{Client: mock}
@@ -38,11 +39,11 @@ func TestGetBalance(t *testing.T) {
 func TestGetTransactionCount(t *testing.T) {
 	// "0x10" 表示 nonce 为 16
 	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method == "getTransactionCount" {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method == types.GetTransactionCount {
 				return json.RawMessage("\"0x10\""), nil
 			}
-			return nil, errors.New("unexpected method: " + method)
+			return nil, fmt.Errorf("unexpected method: %s", method)
 		},
 	}
 	pc := &Client{Client: mock}
@@ -59,11 +60,11 @@ func TestGetTransactionCount(t *testing.T) {
 func TestCreateAccessList(t *testing.T) {
 	mockResponse := `{"accessList": [{"address": "0x0000000000000000000000000000000000000001", "storageKeys": []}]}`
 	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method == "createAccessList" {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method == types.CreateAccessList {
 				return json.RawMessage(mockResponse), nil
 			}
-			return nil, errors.New("unexpected method: " + method)
+			return nil, fmt.Errorf("unexpected method: %s", method)
 		},
 	}
 	pc := &Client{Client: mock}
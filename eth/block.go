@@ -2,7 +2,6 @@ package eth
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/AutoArbi/go-viem/transfer"
 	"github.com/AutoArbi/go-viem/types"
@@ -22,25 +21,32 @@ func (c *Client) GetBlockNumber(ctx context.Context) (*big.Int, error) {
 	return blockNumber.TransferBigInt(res)
 }
 
-// GetBlockByNumber get block information by block number
+// GetBlockByNumber get block information by block number, a nil blockNumber returns the latest block
+// and errors.ErrNotFound is returned for unknown blocks.
+// With fullTx the block contains the full transaction objects instead of only their hashes
 // method: eth_getBlockByNumber
-func (c *Client) GetBlockByNumber(ctx context.Context, blockNumber *big.Int, fullTx bool) (json.RawMessage, error) {
-	blockNumHex := fmt.Sprintf("0x%x", blockNumber)
+func (c *Client) GetBlockByNumber(ctx context.Context, blockNumber *big.Int, fullTx bool) (*types.Block, error) {
+	blockNumHex := string(types.LATEST)
+	if blockNumber != nil {
+		blockNumHex = fmt.Sprintf("0x%x", blockNumber)
+	}
 	res, err := c.Client.Request(ctx, types.GetBlockByNumber, blockNumHex, fullTx)
 	if err != nil {
 		return nil, err
 	}
-	return res, nil
+	block := transfer.NewRPCResponseTransfer()
+	return block.TransferBlock(res)
 }
 
 // GetBlockByHash get block information by block hash
 // method: eth_getBlockByHash
-func (c *Client) GetBlockByHash(ctx context.Context, blockHash common.Hash, fullTx bool) (json.RawMessage, error) {
+func (c *Client) GetBlockByHash(ctx context.Context, blockHash common.Hash, fullTx bool) (*types.Block, error) {
 	res, err := c.Client.Request(ctx, types.GetBlockByHash, blockHash.Hex(), fullTx)
 	if err != nil {
 		return nil, err
 	}
-	return res, nil
+	block := transfer.NewRPCResponseTransfer()
+	return block.TransferBlock(res)
 }
 
 // GetBlockTransactionCountByNumber get transaction count by block number
@@ -66,33 +72,3 @@ func (c *Client) GetBlockTransactionCountByHash(ctx context.Context, blockHash c
 	countHex := transfer.NewRPCResponseTransfer()
 	return countHex.TransferUint64(res)
 }
-
-// SimulateBlocks simulate blocks
-// method: eth_simulateBlocks
-func (c *Client) SimulateBlocks(ctx context.Context, blockCount int) (json.RawMessage, error) {
-	res, err := c.Client.Request(ctx, types.SimulateBlocks, blockCount)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
-}
-
-// WatchBlockNumber watch block number
-// method: eth_watchBlockNumber
-func (c *Client) WatchBlockNumber(ctx context.Context) (json.RawMessage, error) {
-	res, err := c.Client.Request(ctx, types.WatchBlockNumber)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
-}
-
-// WatchBlocks watch blocks
-// method: eth_watchBlocks
-func (c *Client) WatchBlocks(ctx context.Context, blockCount int) (json.RawMessage, error) {
-	res, err := c.Client.Request(ctx, types.WatchBlocks, blockCount)
-	if err != nil {
-		return nil, err
-	}
-	return res, nil
-}
@@ -8,19 +8,60 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/AutoArbi/go-viem/types"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 var _ client.Transport = (*mockClient)(nil)
 
+const testBlockJSON = `{
+	"number": "0x64",
+	"hash": "0x0000000000000000000000000000000000000000000000000000000000000001",
+	"parentHash": "0x0000000000000000000000000000000000000000000000000000000000000002",
+	"miner": "0x0000000000000000000000000000000000000003",
+	"gasLimit": "0x1c9c380",
+	"gasUsed": "0x5208",
+	"timestamp": "0x6553f100",
+	"baseFeePerGas": "0x7",
+	"blobGasUsed": "0x20000",
+	"excessBlobGas": "0x0",
+	"parentBeaconBlockRoot": "0x0000000000000000000000000000000000000000000000000000000000000004",
+	"requestsHash": "0x0000000000000000000000000000000000000000000000000000000000000005",
+	"size": "0x100",
+	"transactions": [{
+		"hash": "0x00000000000000000000000000000000000000000000000000000000000000aa",
+		"blockHash": "0x0000000000000000000000000000000000000000000000000000000000000001",
+		"blockNumber": "0x64",
+		"transactionIndex": "0x0",
+		"type": "0x2",
+		"chainId": "0x1",
+		"from": "0x0000000000000000000000000000000000000006",
+		"to": "0x0000000000000000000000000000000000000007",
+		"nonce": "0x5",
+		"gas": "0x5208",
+		"value": "0x0",
+		"input": "0xa9059cbb",
+		"gasPrice": "0x9",
+		"maxFeePerGas": "0x64",
+		"maxPriorityFeePerGas": "0x2",
+		"accessList": [],
+		"v": "0x0",
+		"r": "0x1",
+		"s": "0x2",
+		"yParity": "0x0"
+	}],
+	"uncles": [],
+	"withdrawals": [{"index": "0x1", "validatorIndex": "0x2", "address": "0x0000000000000000000000000000000000000008", "amount": "0x3e8"}]
+}`
+
 func TestGetBlockByNumber(t *testing.T) {
 	expectedBlockNumber := big.NewInt(100)
 	expectedParam := fmt.Sprintf("0x%x", expectedBlockNumber) // "0x64"
 	fullTx := true
 
 	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method != "getBlockByNumber" {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetBlockByNumber {
 				return nil, fmt.Errorf("unexpected method: %s", method)
 			}
 			if len(params) != 2 {
@@ -32,20 +73,38 @@ func TestGetBlockByNumber(t *testing.T) {
 			if params[1] != fullTx {
 				return nil, fmt.Errorf("expected fullTx %v, got %v", fullTx, params[1])
 			}
-			return json.RawMessage("{\"block\": \"data\"}"), nil
+			return json.RawMessage(testBlockJSON), nil
 		},
 	}
 	pc := &Client{Client: mock}
-	res, err := pc.GetBlockByNumber(context.Background(), expectedBlockNumber, true)
+	block, err := pc.GetBlockByNumber(context.Background(), expectedBlockNumber, true)
 	if err != nil {
 		t.Fatalf("GetBlockByNumber error: %v", err)
 	}
-	var obj map[string]interface{}
-	if err := json.Unmarshal(res, &obj); err != nil {
-		t.Fatalf("unmarshal error: %v", err)
+	if block.Number.Cmp(expectedBlockNumber) != 0 {
+		t.Errorf("expected block number %s, got %s", expectedBlockNumber, block.Number)
+	}
+	if block.BaseFeePerGas == nil || block.BaseFeePerGas.Int64() != 7 {
+		t.Errorf("expected baseFeePerGas 7, got %v", block.BaseFeePerGas)
+	}
+	if block.BlobGasUsed == nil || *block.BlobGasUsed != 131072 || block.ExcessBlobGas == nil || *block.ExcessBlobGas != 0 {
+		t.Errorf("unexpected blob gas fields: %v, %v", block.BlobGasUsed, block.ExcessBlobGas)
+	}
+	if block.ParentBeaconBlockRoot == nil || block.RequestsHash == nil {
+		t.Error("expected parentBeaconBlockRoot and requestsHash")
+	}
+	if len(block.Transactions) != 1 || len(block.TransactionHashes) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(block.Transactions))
+	}
+	tx := block.Transactions[0]
+	if tx.Type != 2 || tx.Nonce != 5 || tx.MaxFeePerGas.Int64() != 100 || tx.To == nil || len(tx.Input) != 4 {
+		t.Errorf("unexpected transaction: %+v", tx)
+	}
+	if block.TransactionHashes[0] != tx.Hash {
+		t.Errorf("expected transaction hash %s, got %s", tx.Hash, block.TransactionHashes[0])
 	}
-	if obj["block"] != "data" {
-		t.Errorf("expected block data 'data', got %v", obj["block"])
+	if len(block.Withdrawals) != 1 || block.Withdrawals[0].Amount != 1000 {
+		t.Errorf("unexpected withdrawals: %v", block.Withdrawals)
 	}
 }
 
@@ -54,8 +113,8 @@ func TestGetBlockByHash(t *testing.T) {
 	fullTx := false
 
 	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method != "getBlockByHash" {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetBlockByHash {
 				return nil, fmt.Errorf("unexpected method: %s", method)
 			}
 			if len(params) != 2 {
@@ -67,20 +126,19 @@ func TestGetBlockByHash(t *testing.T) {
 			if params[1] != fullTx {
 				return nil, fmt.Errorf("expected fullTx %v, got %v", fullTx, params[1])
 			}
-			return json.RawMessage("{\"block\": \"hash data\"}"), nil
+			return json.RawMessage(`{"number":"0x1","hash":"` + expectedBlockHash.Hex() + `","transactions":["0x00000000000000000000000000000000000000000000000000000000000000aa"],"uncles":[]}`), nil
 		},
 	}
 	pc := &Client{Client: mock}
-	res, err := pc.GetBlockByHash(context.Background(), expectedBlockHash, fullTx)
+	block, err := pc.GetBlockByHash(context.Background(), expectedBlockHash, fullTx)
 	if err != nil {
 		t.Fatalf("GetBlockByHash error: %v", err)
 	}
-	var obj map[string]interface{}
-	if err := json.Unmarshal(res, &obj); err != nil {
-		t.Fatalf("unmarshal error: %v", err)
+	if block.Hash != expectedBlockHash {
+		t.Errorf("expected block hash %s, got %s", expectedBlockHash.Hex(), block.Hash.Hex())
 	}
-	if obj["block"] != "hash data" {
-		t.Errorf("expected block data 'hash data', got %v", obj["block"])
+	if block.Transactions != nil || len(block.TransactionHashes) != 1 || block.TransactionHashes[0] != common.HexToHash("0xaa") {
+		t.Errorf("expected one transaction hash, got %v", block.TransactionHashes)
 	}
 }
 
@@ -88,8 +146,8 @@ func TestGetBlockTransactionCountByNumber(t *testing.T) {
 	expectedBlockNumber := big.NewInt(200)
 	expectedParam := fmt.Sprintf("0x%x", expectedBlockNumber)
 	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method != "getBlockTransactionCountByNumber" {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetBlockTransactionCountByNumber {
 				return nil, fmt.Errorf("unexpected method: %s", method)
 			}
 			if len(params) != 1 {
@@ -114,8 +172,8 @@ func TestGetBlockTransactionCountByNumber(t *testing.T) {
 func TestGetBlockTransactionCountByHash(t *testing.T) {
 	expectedBlockHash := common.HexToHash("0xdef456")
 	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method != "getBlockTransactionCountByHash" {
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetBlockTransactionCountByHash {
 				return nil, fmt.Errorf("unexpected method: %s", method)
 			}
 			if len(params) != 1 {
@@ -136,86 +194,3 @@ func TestGetBlockTransactionCountByHash(t *testing.T) {
 		t.Errorf("expected transaction count 3, got %d", count)
 	}
 }
-
-func TestSimulateBlocks(t *testing.T) {
-	blockCount := 10
-	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method != "simulateBlocks" {
-				return nil, fmt.Errorf("unexpected method: %s", method)
-			}
-			if len(params) != 1 {
-				return nil, fmt.Errorf("expected 1 param, got %d", len(params))
-			}
-			if bc, ok := params[0].(int); !ok || bc != blockCount {
-				return nil, fmt.Errorf("expected blockCount %d, got %v", blockCount, params[0])
-			}
-			return json.RawMessage("{\"simulated\": true}"), nil
-		},
-	}
-	pc := &Client{Client: mock}
-	res, err := pc.SimulateBlocks(context.Background(), blockCount)
-	if err != nil {
-		t.Fatalf("SimulateBlocks error: %v", err)
-	}
-	var obj map[string]interface{}
-	if err := json.Unmarshal(res, &obj); err != nil {
-		t.Fatalf("unmarshal error: %v", err)
-	}
-	if simulated, ok := obj["simulated"].(bool); !ok || !simulated {
-		t.Errorf("expected simulated true, got %v", obj["simulated"])
-	}
-}
-
-func TestWatchBlockNumber(t *testing.T) {
-	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method != "watchBlockNumber" {
-				return nil, fmt.Errorf("unexpected method: %s", method)
-			}
-			return json.RawMessage("{\"watch\": \"blockNumber\"}"), nil
-		},
-	}
-	pc := &Client{Client: mock}
-	res, err := pc.WatchBlockNumber(context.Background())
-	if err != nil {
-		t.Fatalf("WatchBlockNumber error: %v", err)
-	}
-	var obj map[string]interface{}
-	if err := json.Unmarshal(res, &obj); err != nil {
-		t.Fatalf("unmarshal error: %v", err)
-	}
-	if obj["watch"] != "blockNumber" {
-		t.Errorf("expected watch value 'blockNumber', got %v", obj["watch"])
-	}
-}
-
-func TestWatchBlocks(t *testing.T) {
-	blockCount := 5
-	mock := &mockClient{
-		requestFunc: func(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
-			if method != "watchBlocks" {
-				return nil, fmt.Errorf("unexpected method: %s", method)
-			}
-			if len(params) != 1 {
-				return nil, fmt.Errorf("expected 1 param, got %d", len(params))
-			}
-			if bc, ok := params[0].(int); !ok || bc != blockCount {
-				return nil, fmt.Errorf("expected blockCount %d, got %v", blockCount, params[0])
-			}
-			return json.RawMessage("{\"watch\": \"blocks\"}"), nil
-		},
-	}
-	pc := &Client{Client: mock}
-	res, err := pc.WatchBlocks(context.Background(), blockCount)
-	if err != nil {
-		t.Fatalf("WatchBlocks error: %v", err)
-	}
-	var obj map[string]interface{}
-	if err := json.Unmarshal(res, &obj); err != nil {
-		t.Fatalf("unmarshal error: %v", err)
-	}
-	if obj["watch"] != "blocks" {
-		t.Errorf("expected watch value 'blocks', got %v", obj["watch"])
-	}
-}
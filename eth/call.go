@@ -0,0 +1,180 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AutoArbi/go-viem/transfer"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// CallOption configures the state and block context of call and estimate actions
+type CallOption func(*callConfig)
+
+type callConfig struct {
+	blockTag       types.BlockTag
+	stateOverride  types.StateOverride
+	blockOverrides *types.BlockOverrides
+}
+
+// WithBlockTag estimates the gas on the state of a block, a tag or types.BlockNumber. Call takes its block as argument
+func WithBlockTag(blockTag types.BlockTag) CallOption {
+	return func(c *callConfig) {
+		c.blockTag = blockTag
+	}
+}
+
+// WithStateOverride executes the call with the balance, nonce, code or storage of accounts replaced
+func WithStateOverride(override types.StateOverride) CallOption {
+	return func(c *callConfig) {
+		c.stateOverride = override
+	}
+}
+
+// WithBlockOverrides executes the call with the number, timestamp, base fee or coinbase of the block replaced
+func WithBlockOverrides(overrides types.BlockOverrides) CallOption {
+	return func(c *callConfig) {
+		c.blockOverrides = &overrides
+	}
+}
+
+// newCallConfig applies opts
+func newCallConfig(opts []CallOption) *callConfig {
+	cfg := &callConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// overrideParams returns the optional state and block override parameters following the block parameter
+func (c *callConfig) overrideParams() []any {
+	switch {
+	case c.blockOverrides != nil:
+		return []any{c.stateOverride, c.blockOverrides}
+	case c.stateOverride != nil:
+		return []any{c.stateOverride}
+	}
+	return nil
+}
+
+// ReadContractOptions configures ReadContract
+type ReadContractOptions struct {
+	// From is the sender of the call
+	From *common.Address
+	// BlockTag is the block the call is executed on, a tag or types.BlockNumber, default is "latest"
+	BlockTag types.BlockTag
+	// Result receives the decoded outputs when set, a pointer to a struct with a field per output
+	// or a pointer to the type of a single output
+	Result any
+	// StateOverride and BlockOverrides change the state and block context of the call
+	StateOverride  types.StateOverride
+	BlockOverrides *types.BlockOverrides
+	// ErrorABIs are decoded against in addition to the contract's ABI when the call reverts with a custom error,
+	// e.g. the ABIs of contracts called by the target
+	ErrorABIs []string
+}
+
+// Call executes a message call on the state of a block without creating a transaction, default blockTag is "latest".
+// A revert is returned as *util.ContractRevertError
+// method: eth_call
+func (c *Client) Call(ctx context.Context, call map[string]any, blockTag types.BlockTag, opts ...CallOption) ([]byte, error) {
+	if blockTag == "" {
+		blockTag = types.LATEST
+	}
+	params := append([]any{call, blockTag}, newCallConfig(opts).overrideParams()...)
+	res, err := c.Client.Request(ctx, types.Call, params...)
+	if err != nil {
+		if revertErr := util.RevertFromError(err); revertErr != nil {
+			return nil, revertErr
+		}
+		return nil, err
+	}
+	result, err := transfer.NewRPCResponseTransfer().TransferBytes(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse call result: %w", err)
+	}
+	return result, nil
+}
+
+// ReadContract calls a function of the contract at address and decodes its outputs, the ABI may be JSON or human-readable.
+// A revert is returned as *util.ContractRevertError with custom errors decoded against the contract's ABI and opts.ErrorABIs
+// method: eth_call
+func (c *Client) ReadContract(ctx context.Context, contractABI string, address common.Address, functionName string, args []any, opts *ReadContractOptions) ([]any, error) {
+	if opts == nil {
+		opts = &ReadContractOptions{}
+	}
+	parsed, err := util.ParseABI(contractABI)
+	if err != nil {
+		return nil, err
+	}
+	errorABIs, err := parseErrorABIs(parsed, opts.ErrorABIs)
+	if err != nil {
+		return nil, err
+	}
+	data, err := parsed.Pack(functionName, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s call: %w", functionName, err)
+	}
+
+	call := map[string]any{
+		"to":   address,
+		"data": hexutil.Bytes(data),
+	}
+	if opts.From != nil {
+		call["from"] = *opts.From
+	}
+	output, err := c.Call(ctx, call, opts.BlockTag, opts.callOptions()...)
+	if err != nil {
+		var revertErr *util.ContractRevertError
+		if errors.As(err, &revertErr) && len(revertErr.Data) > 0 {
+			return nil, util.DecodeRevert(revertErr.Data, errorABIs...)
+		}
+		return nil, err
+	}
+
+	values, err := parsed.Unpack(functionName, output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s result: %w", functionName, err)
+	}
+	if opts.Result != nil {
+		if err := parsed.Methods[functionName].Outputs.Copy(opts.Result, values); err != nil {
+			return nil, fmt.Errorf("failed to decode %s result: %w", functionName, err)
+		}
+	}
+	return values, nil
+}
+
+// parseErrorABIs returns the ABIs a revert of a call to contractABI is decoded against
+func parseErrorABIs(contractABI *abi.ABI, errorABIs []string) ([]*abi.ABI, error) {
+	abis := []*abi.ABI{contractABI}
+	for _, errorABI := range errorABIs {
+		parsed, err := util.ParseABI(errorABI)
+		if err != nil {
+			return nil, err
+		}
+		abis = append(abis, parsed)
+	}
+	return abis, nil
+}
+
+// callOptions returns the call options for the overrides
+func (o *ReadContractOptions) callOptions() []CallOption {
+	return overrideOptions(o.StateOverride, o.BlockOverrides)
+}
+
+// overrideOptions turns the override fields of action options into call options
+func overrideOptions(stateOverride types.StateOverride, blockOverrides *types.BlockOverrides) []CallOption {
+	var opts []CallOption
+	if stateOverride != nil {
+		opts = append(opts, WithStateOverride(stateOverride))
+	}
+	if blockOverrides != nil {
+		opts = append(opts, WithBlockOverrides(*blockOverrides))
+	}
+	return opts
+}
@@ -0,0 +1,205 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const erc20ABI = `
+function balanceOf(address owner) view returns (uint256)
+function getReserves() view returns (uint112 reserve0, uint112 reserve1, uint32 blockTimestampLast)
+`
+
+// testRevertError mimics the error geth returns for reverted calls
+type testRevertError struct {
+	data string
+}
+
+func (e *testRevertError) Error() string          { return "execution reverted" }
+func (e *testRevertError) ErrorCode() int         { return 3 }
+func (e *testRevertError) ErrorData() interface{} { return e.data }
+
+func TestReadContract(t *testing.T) {
+	parsed, _ := util.ParseABI(erc20ABI)
+	token := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	owner := common.HexToAddress("0x1")
+
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.Call {
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+			call := params[0].(map[string]any)
+			if call["to"] != token || params[1] != types.BlockNumber(big.NewInt(100)) && params[1] != types.LATEST {
+				return nil, fmt.Errorf("unexpected call: %v %v", call, params[1])
+			}
+			data := call["data"].(hexutil.Bytes)
+			fn, err := parsed.MethodById(data)
+			if err != nil {
+				return nil, err
+			}
+			var output []byte
+			switch fn.Name {
+			case "balanceOf":
+				output, _ = fn.Outputs.Pack(big.NewInt(1234))
+			case "getReserves":
+				output, _ = fn.Outputs.Pack(big.NewInt(10), big.NewInt(20), uint32(30))
+			}
+			return json.RawMessage(fmt.Sprintf(`"%s"`, hexutil.Encode(output))), nil
+		},
+	}
+	pc := &Client{Client: mock}
+
+	values, err := pc.ReadContract(context.Background(), erc20ABI, token, "balanceOf", []any{owner}, &ReadContractOptions{
+		BlockTag: types.BlockNumber(big.NewInt(100)),
+	})
+	if err != nil {
+		t.Fatalf("ReadContract error: %v", err)
+	}
+	if len(values) != 1 || values[0].(*big.Int).Int64() != 1234 {
+		t.Errorf("unexpected values: %v", values)
+	}
+
+	var reserves struct {
+		Reserve0           *big.Int
+		Reserve1           *big.Int
+		BlockTimestampLast uint32
+	}
+	if _, err := pc.ReadContract(context.Background(), erc20ABI, token, "getReserves", nil, &ReadContractOptions{Result: &reserves}); err != nil {
+		t.Fatalf("ReadContract error: %v", err)
+	}
+	if reserves.Reserve0.Int64() != 10 || reserves.Reserve1.Int64() != 20 || reserves.BlockTimestampLast != 30 {
+		t.Errorf("unexpected reserves: %+v", reserves)
+	}
+}
+
+func TestReadContract_Revert(t *testing.T) {
+	// Error(string) with "insufficient balance"
+	revertData := "0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000014" +
+		"696e73756666696369656e742062616c616e6365000000000000000000000000"
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			return nil, fmt.Errorf("request failed: %w", &testRevertError{data: revertData})
+		},
+	}
+	pc := &Client{Client: mock}
+
+	_, err := pc.ReadContract(context.Background(), erc20ABI, common.HexToAddress("0x2"), "balanceOf", []any{common.HexToAddress("0x1")}, nil)
+	var revertErr *util.ContractRevertError
+	if !errors.As(err, &revertErr) {
+		t.Fatalf("expected ContractRevertError, got %v", err)
+	}
+	if revertErr.Reason != "insufficient balance" || hexutil.Encode(revertErr.Data) != revertData {
+		t.Errorf("unexpected revert error: %+v", revertErr)
+	}
+}
+
+func TestReadContract_CustomError(t *testing.T) {
+	const poolABI = `
+function swap(uint256 amountIn) returns (uint256)
+error SlippageExceeded(uint256 amountOut)
+`
+	parsed, _ := util.ParseABI(poolABI)
+	customErr := parsed.Errors["SlippageExceeded"]
+	args, _ := customErr.Inputs.Pack(big.NewInt(7))
+	revertData := hexutil.Encode(append(customErr.ID[:4:4], args...))
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			return nil, &testRevertError{data: revertData}
+		},
+	}
+	pc := &Client{Client: mock}
+
+	_, err := pc.ReadContract(context.Background(), poolABI, common.HexToAddress("0x2"), "swap", []any{big.NewInt(1)}, nil)
+	var revertErr *util.ContractRevertError
+	if !errors.As(err, &revertErr) {
+		t.Fatalf("expected ContractRevertError, got %v", err)
+	}
+	if revertErr.Name != "SlippageExceeded" || revertErr.Args[0].(*big.Int).Int64() != 7 {
+		t.Errorf("unexpected revert error: %+v", revertErr)
+	}
+}
+
+func TestCall_Overrides(t *testing.T) {
+	var got []string
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			encoded, err := json.Marshal(params)
+			if err != nil {
+				return nil, err
+			}
+			got = append(got, string(encoded))
+			if method == types.EstimateGas {
+				return json.RawMessage(`"0x5208"`), nil
+			}
+			return json.RawMessage(`"0x"`), nil
+		},
+	}
+	pc := &Client{Client: mock}
+
+	nonce := uint64(7)
+	timestamp := uint64(1700000000)
+	coinbase := common.HexToAddress("0xc0")
+	stateOverride := types.StateOverride{
+		common.HexToAddress("0x1"): {
+			Balance: big.NewInt(1e18),
+			Nonce:   &nonce,
+			Code:    []byte{0x60, 0x00},
+			StateDiff: map[common.Hash]common.Hash{
+				common.HexToHash("0x0"): common.HexToHash("0x1"),
+			},
+		},
+		common.HexToAddress("0x2"): {State: map[common.Hash]common.Hash{}},
+	}
+	blockOverrides := types.BlockOverrides{Number: big.NewInt(100), Time: &timestamp, BaseFeePerGas: big.NewInt(0), FeeRecipient: &coinbase}
+	call := map[string]any{"to": common.HexToAddress("0x3")}
+
+	if _, err := pc.Call(context.Background(), call, "", WithStateOverride(stateOverride), WithBlockOverrides(blockOverrides)); err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	if _, err := pc.Call(context.Background(), call, "", WithBlockOverrides(blockOverrides)); err != nil {
+		t.Fatalf("Call error: %v", err)
+	}
+	if _, err := pc.EstimateGas(context.Background(), call, WithStateOverride(stateOverride)); err != nil {
+		t.Fatalf("EstimateGas error: %v", err)
+	}
+	if _, err := pc.EstimateGas(context.Background(), call); err != nil {
+		t.Fatalf("EstimateGas error: %v", err)
+	}
+	if _, err := pc.EstimateGas(context.Background(), call, WithBlockTag(types.PENDING), WithStateOverride(stateOverride)); err != nil {
+		t.Fatalf("EstimateGas error: %v", err)
+	}
+	if _, err := pc.EstimateGas(context.Background(), call, WithBlockTag(types.BlockNumber(big.NewInt(100)))); err != nil {
+		t.Fatalf("EstimateGas error: %v", err)
+	}
+
+	const (
+		to     = `{"to":"0x0000000000000000000000000000000000000003"}`
+		state  = `{"0x0000000000000000000000000000000000000001":{"balance":"0xde0b6b3a7640000","nonce":"0x7","code":"0x6000","stateDiff":{"0x0000000000000000000000000000000000000000000000000000000000000000":"0x0000000000000000000000000000000000000000000000000000000000000001"}},"0x0000000000000000000000000000000000000002":{"state":{}}}`
+		blocks = `{"number":"0x64","time":"0x6553f100","feeRecipient":"0x00000000000000000000000000000000000000c0","baseFeePerGas":"0x0"}`
+	)
+	want := []string{
+		`[` + to + `,"latest",` + state + `,` + blocks + `]`,
+		`[` + to + `,"latest",null,` + blocks + `]`,
+		`[` + to + `,"latest",` + state + `]`,
+		`[` + to + `]`,
+		`[` + to + `,"pending",` + state + `]`,
+		`[` + to + `,"0x64"]`,
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d params\ngot  %s\nwant %s", i, got[i], want[i])
+		}
+	}
+}
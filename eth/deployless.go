@@ -0,0 +1,115 @@
+package eth
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+)
+
+// deploylessBatchCode is the init code of a contract whose constructor executes a list of calls and returns their
+// results. Sent as eth_call without a target it runs several calls without any deployed helper contract.
+// The calls are appended to the code, each as
+//
+//	target (32 bytes) | allowFailure (32 bytes) | calldata length (32 bytes) | calldata
+//
+// and the results are returned back to back as
+//
+//	success (32 bytes) | returndata length (32 bytes) | returndata
+//
+// A failed call that does not allow failure reverts the whole batch with its revert data.
+//
+//	      PUSH2 end CODESIZE SUB DUP1 PUSH2 end PUSH1 0 CODECOPY    ; copy the calls to memory[0:n]
+//	      DUP1 PUSH1 0                                              ; ptr=0 out=n end=n
+//	loop: JUMPDEST DUP3 DUP2 LT ISZERO PUSH2 done JUMPI
+//	      PUSH1 0 PUSH1 0 DUP3 PUSH1 64 ADD MLOAD DUP4 PUSH1 96 ADD
+//	      PUSH1 0 DUP6 MLOAD GAS CALL                               ; call(gas, target, 0, ptr+96, length, 0, 0)
+//	      DUP1 ISZERO DUP3 PUSH1 32 ADD MLOAD ISZERO AND PUSH2 fail JUMPI
+//	      DUP3 MSTORE                                               ; memory[out] = success
+//	      RETURNDATASIZE DUP3 PUSH1 32 ADD MSTORE                   ; memory[out+32] = returndatasize
+//	      RETURNDATASIZE PUSH1 0 DUP4 PUSH1 64 ADD RETURNDATACOPY   ; memory[out+64:] = returndata
+//	      RETURNDATASIZE DUP3 ADD PUSH1 64 ADD SWAP2 POP            ; out += 64 + returndatasize
+//	      DUP1 PUSH1 64 ADD MLOAD ADD PUSH1 96 ADD PUSH2 loop JUMP  ; ptr += 96 + length
+//	done: JUMPDEST POP DUP2 SWAP1 SUB SWAP1 RETURN                  ; return memory[end:out]
+//	fail: JUMPDEST RETURNDATASIZE PUSH1 0 PUSH1 0 RETURNDATACOPY RETURNDATASIZE PUSH1 0 REVERT
+//	end:
+var deploylessBatchCode = hexutil.MustDecode("0x61006f38038061006f6000398060005b8281101561005d5760006000826040015183606001600085515af18015826020015115166100645782523d82602001523d6000836040013e3d8201604001915080604001510160600161000f565b5081900390f35b3d600060003e3d6000fd")
+
+// deploylessCall is a call executed by deploylessBatchCode
+type deploylessCall struct {
+	target       common.Address
+	allowFailure bool
+	data         []byte
+}
+
+// deploylessResult is the outcome of a deploylessCall
+type deploylessResult struct {
+	success    bool
+	returnData []byte
+}
+
+// deploylessBatch executes the calls in order within a single eth_call without a deployed contract.
+// Results of calls that allow failure report their failure, any other failed call makes the batch
+// fail with its revert error
+// method: eth_call
+func (c *Client) deploylessBatch(ctx context.Context, calls []deploylessCall, blockTag types.BlockTag, opts ...CallOption) ([]deploylessResult, error) {
+	size := len(deploylessBatchCode)
+	for _, call := range calls {
+		size += 96 + len(call.data)
+	}
+	input := make([]byte, 0, size)
+	input = append(input, deploylessBatchCode...)
+	for _, call := range calls {
+		var allowFailure byte
+		if call.allowFailure {
+			allowFailure = 1
+		}
+		input = append(input, common.LeftPadBytes(call.target.Bytes(), 32)...)
+		input = append(input, common.LeftPadBytes([]byte{allowFailure}, 32)...)
+		input = append(input, common.LeftPadBytes(big.NewInt(int64(len(call.data))).Bytes(), 32)...)
+		input = append(input, call.data...)
+	}
+
+	output, err := c.Call(ctx, map[string]any{"data": hexutil.Bytes(input)}, blockTag, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDeploylessResults(output, len(calls))
+}
+
+// decodeDeploylessResults splits the output of deploylessBatchCode into the results of n calls
+func decodeDeploylessResults(output []byte, n int) ([]deploylessResult, error) {
+	results := make([]deploylessResult, 0, n)
+	for len(output) > 0 {
+		if len(output) < 64 || !isUint64Word(output[32:64]) {
+			return nil, errors.New("malformed deployless call output")
+		}
+		length := binary.BigEndian.Uint64(output[56:64])
+		if uint64(len(output)-64) < length {
+			return nil, errors.New("malformed deployless call output")
+		}
+		results = append(results, deploylessResult{
+			success:    output[31] == 1,
+			returnData: output[64 : 64+length],
+		})
+		output = output[64+length:]
+	}
+	if len(results) != n {
+		return nil, fmt.Errorf("deployless call returned %d results for %d calls", len(results), n)
+	}
+	return results, nil
+}
+
+// isUint64Word reports whether a 32 byte word holds a value that fits into an uint64
+func isUint64Word(word []byte) bool {
+	for _, b := range word[:24] {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
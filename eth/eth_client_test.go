@@ -4,15 +4,25 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"github.com/AutoArbi/go-viem/client"
+	"github.com/AutoArbi/go-viem/types"
 )
 
 type mockClient struct {
-	requestFunc func(ctx context.Context, method string, params ...any) (json.RawMessage, error)
+	requestFunc func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error)
+	batchFunc   func(ctx context.Context, batch []client.BatchElem) error
 }
 
-func (m *mockClient) Request(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
+func (m *mockClient) Request(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
 	if m.requestFunc != nil {
 		return m.requestFunc(ctx, method, params...)
 	}
 	return nil, errors.New("not implemented")
 }
+
+func (m *mockClient) BatchRequest(ctx context.Context, batch []client.BatchElem) error {
+	if m.batchFunc != nil {
+		return m.batchFunc(ctx, batch)
+	}
+	return errors.New("not implemented")
+}
@@ -0,0 +1,43 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetContractEvents gets the logs of the named event and decodes them against the ABI, which may be JSON or
+// human-readable. filter.Topics filters the indexed arguments only, the event selector is added in front of
+// them, see util.EventTopics to build them from values. Logs that share the selector but do not fit the event,
+// e.g. ERC-721 transfers when asking for ERC-20 Transfer, are returned with the decoding error in Err
+// method: eth_getLogs
+func (c *Client) GetContractEvents(ctx context.Context, contractABI, eventName string, filter types.LogFilter) ([]*util.DecodedEvent, error) {
+	parsed, err := util.ParseABI(contractABI)
+	if err != nil {
+		return nil, err
+	}
+	event, ok := parsed.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event %s: %w", eventName, verrors.ErrEventNotFound)
+	}
+	if !event.Anonymous {
+		filter.Topics = append([][]common.Hash{{event.ID}}, filter.Topics...)
+	}
+
+	logs, err := c.GetLogs(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*util.DecodedEvent, 0, len(logs))
+	for _, log := range logs {
+		decoded, err := util.DecodeEventLogByName(parsed, eventName, log)
+		if err != nil {
+			decoded = &util.DecodedEvent{Log: log, Name: eventName, Err: err}
+		}
+		events = append(events, decoded)
+	}
+	return events, nil
+}
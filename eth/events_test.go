@@ -0,0 +1,83 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const swapABI = `event Swap(address indexed sender, address indexed recipient, int256 amount0, int256 amount1, uint160 sqrtPriceX96, uint128 liquidity, int24 tick)`
+
+func TestGetContractEvents(t *testing.T) {
+	parsed, err := util.ParseABI(swapABI)
+	if err != nil {
+		t.Fatalf("ParseABI error: %v", err)
+	}
+	event := parsed.Events["Swap"]
+	data, err := event.Inputs.NonIndexed().Pack(big.NewInt(-1000), big.NewInt(2000), big.NewInt(3), big.NewInt(4), big.NewInt(-5))
+	if err != nil {
+		t.Fatalf("Pack error: %v", err)
+	}
+	pool := common.HexToAddress("0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640")
+	sender := common.HexToAddress("0x1")
+	recipient := common.HexToAddress("0x2")
+	swapLog := fmt.Sprintf(`{"address":"%s","topics":["%s","%s","%s"],"data":"%s","blockNumber":"0x10"}`,
+		pool.Hex(), event.ID.Hex(), common.BytesToHash(sender.Bytes()).Hex(), common.BytesToHash(recipient.Bytes()).Hex(), hexutil.Encode(data))
+	// same selector with a different indexed layout is returned with an error
+	otherLog := fmt.Sprintf(`{"address":"%s","topics":["%s"],"data":"0x","blockNumber":"0x10"}`, pool.Hex(), event.ID.Hex())
+
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetLogs {
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+			filter := params[0].(types.LogFilter)
+			if len(filter.Topics) != 3 || filter.Topics[0][0] != event.ID || filter.Topics[1] != nil || filter.Topics[2][0] != common.BytesToHash(recipient.Bytes()) {
+				return nil, fmt.Errorf("unexpected topics: %v", filter.Topics)
+			}
+			return json.RawMessage("[" + swapLog + "," + otherLog + "]"), nil
+		},
+	}
+	pc := &Client{Client: mock}
+
+	events, err := pc.GetContractEvents(context.Background(), swapABI, "Swap", types.LogFilter{
+		Addresses: []common.Address{pool},
+		Topics:    [][]common.Hash{nil, {common.BytesToHash(recipient.Bytes())}},
+	})
+	if err != nil {
+		t.Fatalf("GetContractEvents error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[1].Err == nil || events[1].Args != nil || events[1].Address != pool {
+		t.Errorf("expected the log that does not fit Swap with an error, got %+v", events[1])
+	}
+	swap := events[0]
+	if swap.Err != nil {
+		t.Fatalf("unexpected decoding error: %v", swap.Err)
+	}
+	if swap.Name != "Swap" || swap.Address != pool || swap.BlockNumber.Int64() != 16 {
+		t.Errorf("unexpected event: %+v", swap)
+	}
+	if swap.Args["sender"] != sender || swap.Args["recipient"] != recipient {
+		t.Errorf("unexpected indexed args: %v", swap.Args)
+	}
+	if swap.Args["amount0"].(*big.Int).Int64() != -1000 || swap.Args["tick"].(*big.Int).Int64() != -5 {
+		t.Errorf("unexpected data args: %v", swap.Args)
+	}
+}
+
+func TestGetContractEvents_UnknownEvent(t *testing.T) {
+	pc := &Client{Client: &mockClient{}}
+	if _, err := pc.GetContractEvents(context.Background(), swapABI, "Mint", types.LogFilter{}); err == nil {
+		t.Fatal("expected error for unknown event")
+	}
+}
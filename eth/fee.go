@@ -0,0 +1,131 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/transfer"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math"
+	"math/big"
+)
+
+// DefaultBaseFeeMultiplier is the default multiplier applied to the base fee by EstimateFeesPerGas
+const DefaultBaseFeeMultiplier = util.DefaultBaseFeeMultiplier
+
+// EstimateFeesPerGasOptions configures EstimateFeesPerGas
+type EstimateFeesPerGasOptions struct {
+	// BaseFeeMultiplier scales the base fee of the latest block to leave room for base fee increases until the
+	// transaction is included, default is DefaultBaseFeeMultiplier. Legacy estimates scale the gas price instead
+	BaseFeeMultiplier float64
+	// Legacy estimates a gas price for legacy transactions instead of EIP-1559 fees
+	Legacy bool
+	// MaxPriorityFeePerGas is used instead of estimating the priority fee
+	MaxPriorityFeePerGas *big.Int
+}
+
+// GetFeeHistory gets the base fees, gas used ratios and the priority fees at the given reward percentiles of
+// blockCount blocks up to newestBlock. Percentiles are between 0 and 100 in ascending order, the reward is
+// omitted without them
+// method: eth_feeHistory
+func (c *Client) GetFeeHistory(ctx context.Context, blockCount uint64, newestBlock types.BlockTag, rewardPercentiles []float64) (*types.FeeHistoryResult, error) {
+	if newestBlock == "" {
+		newestBlock = types.LATEST
+	}
+	if rewardPercentiles == nil {
+		rewardPercentiles = []float64{}
+	}
+	res, err := c.Client.Request(ctx, types.FeeHistory, hexutil.EncodeUint64(blockCount), newestBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	var history types.FeeHistoryResult
+	if err := transfer.NewRPCResponseTransfer().TransferStruct(res, &history); err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// GetGasPrice gets the current gas price in wei
+// method: eth_gasPrice
+func (c *Client) GetGasPrice(ctx context.Context) (*big.Int, error) {
+	res, err := c.Client.Request(ctx, types.GasPrice)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice := transfer.NewRPCResponseTransfer()
+	return gasPrice.TransferBigInt(res)
+}
+
+// GetMaxPriorityFeePerGas gets a priority fee in wei for a timely inclusion. Nodes that reject
+// eth_maxPriorityFeePerGas get the median of the rewards paid in recent blocks
+// method: eth_maxPriorityFeePerGas
+func (c *Client) GetMaxPriorityFeePerGas(ctx context.Context) (*big.Int, error) {
+	res, err := c.Client.Request(ctx, types.MaxPriorityFeePerGas)
+	if err != nil {
+		var rpcErr rpc.Error
+		if !errors.As(err, &rpcErr) {
+			return nil, err
+		}
+		return c.priorityFeeFromHistory(ctx)
+	}
+	priorityFee := transfer.NewRPCResponseTransfer()
+	return priorityFee.TransferBigInt(res)
+}
+
+// priorityFeeFromHistory computes the median of the median rewards of recent blocks, empty blocks are ignored
+// method: eth_feeHistory
+func (c *Client) priorityFeeFromHistory(ctx context.Context) (*big.Int, error) {
+	history, err := c.GetFeeHistory(ctx, util.PriorityFeeHistoryBlocks, types.LATEST, []float64{util.PriorityFeePercentile})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate priority fee from fee history: %w", err)
+	}
+	return util.MedianPriorityFee(history), nil
+}
+
+// EstimateFeesPerGas estimates the fees of a transaction included in one of the next blocks. EIP-1559 fees are
+// maxFeePerGas = baseFee * multiplier + maxPriorityFeePerGas from the latest block, chains without a base fee
+// return errors.ErrEIP1559NotSupported. Legacy estimates return GasPrice = gasPrice * multiplier
+func (c *Client) EstimateFeesPerGas(ctx context.Context, opts *EstimateFeesPerGasOptions) (*types.FeesPerGas, error) {
+	if opts == nil {
+		opts = &EstimateFeesPerGasOptions{}
+	}
+	multiplier := opts.BaseFeeMultiplier
+	if multiplier == 0 {
+		multiplier = DefaultBaseFeeMultiplier
+	}
+	if multiplier < 1 || math.IsInf(multiplier, 0) || math.IsNaN(multiplier) {
+		return nil, fmt.Errorf("invalid base fee multiplier %v, must be at least 1", multiplier)
+	}
+
+	if opts.Legacy {
+		gasPrice, err := c.GetGasPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &types.FeesPerGas{GasPrice: util.MultiplyFee(gasPrice, multiplier)}, nil
+	}
+
+	block, err := c.GetBlockByNumber(ctx, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	if block.BaseFeePerGas == nil {
+		return nil, verrors.ErrEIP1559NotSupported
+	}
+	priorityFee := opts.MaxPriorityFeePerGas
+	if priorityFee == nil {
+		if priorityFee, err = c.GetMaxPriorityFeePerGas(ctx); err != nil {
+			return nil, err
+		}
+	}
+	maxFee := util.MultiplyFee(block.BaseFeePerGas, multiplier)
+	return &types.FeesPerGas{
+		MaxFeePerGas:         maxFee.Add(maxFee, priorityFee),
+		MaxPriorityFeePerGas: new(big.Int).Set(priorityFee),
+	}, nil
+}
@@ -0,0 +1,134 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+)
+
+// testMethodNotFoundError mimics the error of nodes that do not implement a method
+type testMethodNotFoundError struct{}
+
+func (e *testMethodNotFoundError) Error() string  { return "the method does not exist/is not available" }
+func (e *testMethodNotFoundError) ErrorCode() int { return -32601 }
+
+func TestGetFeeHistory(t *testing.T) {
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.FeeHistory || params[0] != "0x2" || params[1] != types.LATEST {
+				return nil, fmt.Errorf("unexpected request: %s %v", method, params)
+			}
+			return json.RawMessage(`{
+				"oldestBlock": "0x10",
+				"baseFeePerGas": ["0x64", "0x6e", "0x78"],
+				"gasUsedRatio": [0.5, 0.9],
+				"reward": [["0x1", "0x2"], ["0x3", "0x4"]],
+				"baseFeePerBlobGas": ["0x1", "0x1", "0x2"],
+				"blobGasUsedRatio": [0, 0.5]
+			}`), nil
+		},
+	}
+	pc := &Client{Client: mock}
+
+	history, err := pc.GetFeeHistory(context.Background(), 2, "", []float64{25, 75})
+	if err != nil {
+		t.Fatalf("GetFeeHistory error: %v", err)
+	}
+	if history.OldestBlock.Int64() != 16 || len(history.BaseFeePerGas) != 3 || history.BaseFeePerGas[2].Int64() != 120 {
+		t.Errorf("unexpected base fees: %v %v", history.OldestBlock, history.BaseFeePerGas)
+	}
+	if len(history.Reward) != 2 || history.Reward[1][1].Int64() != 4 || history.GasUsedRatio[1] != 0.9 {
+		t.Errorf("unexpected rewards: %v %v", history.Reward, history.GasUsedRatio)
+	}
+	if history.BaseFeePerBlobGas[2].Int64() != 2 || history.BlobGasUsedRatio[1] != 0.5 {
+		t.Errorf("unexpected blob fees: %v %v", history.BaseFeePerBlobGas, history.BlobGasUsedRatio)
+	}
+}
+
+func TestGetMaxPriorityFeePerGas_Fallback(t *testing.T) {
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			switch method {
+			case types.MaxPriorityFeePerGas:
+				return nil, &testMethodNotFoundError{}
+			case types.FeeHistory:
+				return json.RawMessage(`{
+					"oldestBlock": "0x1",
+					"baseFeePerGas": ["0x1", "0x1", "0x1", "0x1"],
+					"gasUsedRatio": [0.5, 0, 0.7],
+					"reward": [["0x5"], ["0x0"], ["0x9"]]
+				}`), nil
+			}
+			return nil, fmt.Errorf("unexpected method: %s", method)
+		},
+	}
+	pc := &Client{Client: mock}
+
+	fee, err := pc.GetMaxPriorityFeePerGas(context.Background())
+	if err != nil {
+		t.Fatalf("GetMaxPriorityFeePerGas error: %v", err)
+	}
+	// the empty block is ignored, the median of [5, 9] is 9
+	if fee.Int64() != 9 {
+		t.Errorf("expected priority fee 9, got %v", fee)
+	}
+}
+
+func TestEstimateFeesPerGas(t *testing.T) {
+	baseFee := `"0x3b9aca00"`
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			switch method {
+			case types.GetBlockByNumber:
+				return json.RawMessage(fmt.Sprintf(`{"number": "0x1", "baseFeePerGas": %s}`, baseFee)), nil
+			case types.MaxPriorityFeePerGas:
+				return json.RawMessage(`"0x5f5e100"`), nil
+			case types.GasPrice:
+				return json.RawMessage(`"0x77359400"`), nil
+			}
+			return nil, fmt.Errorf("unexpected method: %s", method)
+		},
+	}
+	pc := &Client{Client: mock}
+	ctx := context.Background()
+
+	fees, err := pc.EstimateFeesPerGas(ctx, nil)
+	if err != nil {
+		t.Fatalf("EstimateFeesPerGas error: %v", err)
+	}
+	// 1 gwei * 1.2 + 0.1 gwei
+	if fees.MaxFeePerGas.Int64() != 1_300_000_000 || fees.MaxPriorityFeePerGas.Int64() != 100_000_000 || fees.GasPrice != nil {
+		t.Errorf("unexpected fees: %+v", fees)
+	}
+
+	fees, err = pc.EstimateFeesPerGas(ctx, &EstimateFeesPerGasOptions{BaseFeeMultiplier: 2, MaxPriorityFeePerGas: big.NewInt(1)})
+	if err != nil {
+		t.Fatalf("EstimateFeesPerGas error: %v", err)
+	}
+	if fees.MaxFeePerGas.Int64() != 2_000_000_001 || fees.MaxPriorityFeePerGas.Int64() != 1 {
+		t.Errorf("unexpected fees: %+v", fees)
+	}
+
+	fees, err = pc.EstimateFeesPerGas(ctx, &EstimateFeesPerGasOptions{Legacy: true, BaseFeeMultiplier: 1.1})
+	if err != nil {
+		t.Fatalf("EstimateFeesPerGas error: %v", err)
+	}
+	if fees.GasPrice.Int64() != 2_200_000_000 || fees.MaxFeePerGas != nil {
+		t.Errorf("unexpected fees: %+v", fees)
+	}
+
+	if _, err := pc.EstimateFeesPerGas(ctx, &EstimateFeesPerGasOptions{BaseFeeMultiplier: 0.5}); err == nil {
+		t.Error("expected error for multiplier below 1")
+	}
+
+	baseFee = "null"
+	if _, err := pc.EstimateFeesPerGas(ctx, nil); !errors.Is(err, verrors.ErrEIP1559NotSupported) {
+		t.Errorf("expected ErrEIP1559NotSupported, got %v", err)
+	}
+}
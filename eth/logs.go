@@ -0,0 +1,134 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AutoArbi/go-viem/transfer"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"strings"
+)
+
+// limitExceededCode is the JSON-RPC error code EIP-1474 assigns to exceeded request limits
+const limitExceededCode = -32005
+
+// logRangeErrors are message fragments providers use to reject eth_getLogs ranges that are too large
+var logRangeErrors = []string{
+	"query returned more than",
+	"block range too large",
+	"block range is too large",
+	"response size exceeded",
+	"response size should not",
+	"too many blocks",
+	"too many results",
+	"exceed maximum block range",
+}
+
+// GetLogs gets all logs matching the filter.
+// When the provider rejects the block range as too large or the result as too big, the range is split in
+// halves and every half the provider rejects is split again, the results of all chunks are merged in block order
+// method: eth_getLogs
+func (c *Client) GetLogs(ctx context.Context, filter types.LogFilter) ([]types.Log, error) {
+	logs, logsErr := c.getLogs(ctx, filter)
+	if logsErr == nil || filter.BlockHash != nil || !isLogRangeError(logsErr) {
+		return logs, logsErr
+	}
+
+	from, err := c.resolveBlockNumber(ctx, filter.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	to, err := c.resolveBlockNumber(ctx, filter.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from.Cmp(to) > 0 {
+		return nil, fmt.Errorf("get logs: from block %s is after to block %s: %w", from, to, logsErr)
+	}
+	return c.getLogsSplit(ctx, filter, from, to, logsErr)
+}
+
+// getLogsRange gets the logs of the blocks from..to, splitting the range when the provider rejects it
+func (c *Client) getLogsRange(ctx context.Context, filter types.LogFilter, from, to *big.Int) ([]types.Log, error) {
+	filter.FromBlock = types.BlockNumber(from)
+	filter.ToBlock = types.BlockNumber(to)
+	logs, err := c.getLogs(ctx, filter)
+	if err == nil {
+		return logs, nil
+	}
+	if !isLogRangeError(err) {
+		return nil, fmt.Errorf("get logs %s-%s: %w", filter.FromBlock, filter.ToBlock, err)
+	}
+	return c.getLogsSplit(ctx, filter, from, to, err)
+}
+
+// getLogsSplit gets the logs of the rejected range from..to as two halves, so only the dense parts of a range
+// are fetched in small chunks
+func (c *Client) getLogsSplit(ctx context.Context, filter types.LogFilter, from, to *big.Int, cause error) ([]types.Log, error) {
+	if from.Cmp(to) == 0 {
+		return nil, fmt.Errorf("get logs %s-%s: %w", types.BlockNumber(from), types.BlockNumber(to), cause)
+	}
+	mid := new(big.Int).Add(from, to)
+	mid.Rsh(mid, 1)
+	logs, err := c.getLogsRange(ctx, filter, from, mid)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := c.getLogsRange(ctx, filter, new(big.Int).Add(mid, big.NewInt(1)), to)
+	if err != nil {
+		return nil, err
+	}
+	return append(logs, upper...), nil
+}
+
+func (c *Client) getLogs(ctx context.Context, filter types.LogFilter) ([]types.Log, error) {
+	res, err := c.Client.Request(ctx, types.GetLogs, filter)
+	if err != nil {
+		return nil, err
+	}
+	var logs []types.Log
+	if err := transfer.NewRPCResponseTransfer().TransferStruct(res, &logs); err != nil {
+		return nil, fmt.Errorf("failed to parse logs: %w", err)
+	}
+	return logs, nil
+}
+
+// resolveBlockNumber turns a block tag into a block number
+func (c *Client) resolveBlockNumber(ctx context.Context, blockTag types.BlockTag) (*big.Int, error) {
+	switch blockTag {
+	case types.EARLIEST:
+		return big.NewInt(0), nil
+	case "", types.LATEST:
+		return c.GetBlockNumber(ctx)
+	case types.SAFE, types.FINALIZED, types.PENDING:
+		res, err := c.Client.Request(ctx, types.GetBlockByNumber, blockTag, false)
+		if err != nil {
+			return nil, err
+		}
+		block, err := transfer.NewRPCResponseTransfer().TransferBlock(res)
+		if err != nil {
+			return nil, err
+		}
+		return block.Number, nil
+	default:
+		return hexutil.DecodeBig(string(blockTag))
+	}
+}
+
+// isLogRangeError reports whether err is a provider rejecting a too large eth_getLogs request
+func isLogRangeError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == limitExceededCode {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range logRangeErrors {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
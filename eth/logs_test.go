@@ -0,0 +1,166 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestGetLogs_SplitsRange(t *testing.T) {
+	address := common.HexToAddress("0x1")
+	topic := common.HexToHash("0x2")
+	var requests int
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			switch method {
+			case types.GetBlockNumber:
+				return json.RawMessage(`"0x3e7"`), nil
+			case types.GetLogs:
+				requests++
+				filter := params[0].(types.LogFilter)
+				if len(filter.Addresses) != 1 || filter.Addresses[0] != address || filter.Topics[0][0] != topic {
+					return nil, fmt.Errorf("unexpected filter: %+v", filter)
+				}
+				from, fromErr := hexutil.DecodeBig(string(filter.FromBlock))
+				to, toErr := hexutil.DecodeBig(string(filter.ToBlock))
+				if fromErr != nil || toErr != nil || new(big.Int).Sub(to, from).Int64() >= 100 {
+					return nil, errors.New("query returned more than 10000 results")
+				}
+				// one log every 50 blocks
+				var logs []string
+				for n := from.Int64(); n <= to.Int64(); n++ {
+					if n%50 == 0 {
+						logs = append(logs, fmt.Sprintf(`{"address":"%s","topics":["%s"],"data":"0x","blockNumber":"0x%x"}`, address.Hex(), topic.Hex(), n))
+					}
+				}
+				return json.RawMessage("[" + strings.Join(logs, ",") + "]"), nil
+			default:
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+		},
+	}
+	pc := &Client{Client: mock}
+
+	logs, err := pc.GetLogs(context.Background(), types.LogFilter{
+		Addresses: []common.Address{address},
+		Topics:    [][]common.Hash{{topic}},
+		FromBlock: types.EARLIEST,
+	})
+	if err != nil {
+		t.Fatalf("GetLogs error: %v", err)
+	}
+	if len(logs) != 20 {
+		t.Fatalf("expected 20 logs, got %d", len(logs))
+	}
+	for i, log := range logs {
+		if log.BlockNumber.Int64() != int64(i*50) {
+			t.Fatalf("expected log %d at block %d, got %s", i, i*50, log.BlockNumber)
+		}
+	}
+	// every rejected range is requested once: 1000, 2x500, 4x250, 8x125 blocks, then 16 accepted chunks
+	if requests != 31 {
+		t.Errorf("expected 31 requests, got %d", requests)
+	}
+}
+
+func TestGetLogs_NoSplitOnOtherErrors(t *testing.T) {
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			return nil, errors.New("invalid argument 0: hex string without 0x prefix")
+		},
+	}
+	pc := &Client{Client: mock}
+	if _, err := pc.GetLogs(context.Background(), types.LogFilter{FromBlock: types.EARLIEST}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestLogFilter_MarshalJSON(t *testing.T) {
+	hash := common.HexToHash("0xabc")
+	filter := types.LogFilter{
+		Topics:    [][]common.Hash{{common.HexToHash("0x1")}, nil, {common.HexToHash("0x2"), common.HexToHash("0x3")}},
+		BlockHash: &hash,
+		FromBlock: types.LATEST,
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	var dec map[string]any
+	if err := json.Unmarshal(data, &dec); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+	if _, ok := dec["fromBlock"]; ok {
+		t.Error("fromBlock must be omitted together with blockHash")
+	}
+	topics := dec["topics"].([]any)
+	if len(topics) != 3 || topics[1] != nil || len(topics[2].([]any)) != 2 {
+		t.Errorf("unexpected topics: %v", topics)
+	}
+}
+
+func TestGetLogs_ReversedRange(t *testing.T) {
+	for _, nodeErr := range []string{"invalid block range params", "query returned more than 10000 results"} {
+		mock := &mockClient{
+			requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+				if method != types.GetLogs {
+					return nil, fmt.Errorf("unexpected method: %s", method)
+				}
+				return nil, errors.New(nodeErr)
+			},
+		}
+		pc := &Client{Client: mock}
+		logs, err := pc.GetLogs(context.Background(), types.LogFilter{FromBlock: "0x20", ToBlock: "0x10"})
+		if err == nil || !strings.Contains(err.Error(), nodeErr) {
+			t.Errorf("expected the node's error %q for a reversed range, got %v (%d logs)", nodeErr, err, len(logs))
+		}
+	}
+}
+
+func TestGetLogs_SplitsOnlyDenseRanges(t *testing.T) {
+	var (
+		requests    int
+		largestSpan int64
+	)
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetLogs {
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+			requests++
+			filter := params[0].(types.LogFilter)
+			from, _ := hexutil.DecodeBig(string(filter.FromBlock))
+			to, _ := hexutil.DecodeBig(string(filter.ToBlock))
+			// one log in each of the first 100 blocks, at most 50 logs per response
+			var logs []string
+			for n := from.Int64(); n <= to.Int64() && n < 100; n++ {
+				logs = append(logs, fmt.Sprintf(`{"address":"0x0000000000000000000000000000000000000001","topics":[],"data":"0x","blockNumber":"0x%x"}`, n))
+			}
+			if len(logs) > 50 {
+				return nil, errors.New("query returned more than 50 results")
+			}
+			largestSpan = max(largestSpan, to.Int64()-from.Int64()+1)
+			return json.RawMessage("[" + strings.Join(logs, ",") + "]"), nil
+		},
+	}
+	pc := &Client{Client: mock}
+
+	logs, err := pc.GetLogs(context.Background(), types.LogFilter{FromBlock: "0x0", ToBlock: "0xfffff"})
+	if err != nil {
+		t.Fatalf("GetLogs error: %v", err)
+	}
+	if len(logs) != 100 || logs[99].BlockNumber.Int64() != 99 {
+		t.Fatalf("expected 100 logs in block order, got %d", len(logs))
+	}
+	if largestSpan < 1<<19 || requests > 50 {
+		t.Errorf("expected the sparse half in one chunk with few requests, largest chunk %d blocks, %d requests", largestSpan, requests)
+	}
+}
@@ -0,0 +1,253 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"sync"
+)
+
+// DefaultMulticallBatchSize is the default limit of calldata bytes per aggregate3 call
+const DefaultMulticallBatchSize = 1024
+
+// DefaultMulticallConcurrency is the default limit of chunks Multicall sends at the same time
+const DefaultMulticallConcurrency = 4
+
+// Multicall3Address is the address Multicall3 is deployed at on most chains
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// multicall3ABI is the part of the Multicall3 ABI used by Multicall
+const multicall3ABI = `function aggregate3((address target, bool allowFailure, bytes callData)[] calls) payable returns ((bool success, bytes returnData)[] returnData)`
+
+// ContractCall is a contract read executed by Multicall
+type ContractCall struct {
+	// ABI is the JSON or human-readable ABI of the contract
+	ABI          string
+	Address      common.Address
+	FunctionName string
+	Args         []any
+	// AllowFailure reports a revert of this call in its result instead of failing the whole multicall
+	AllowFailure bool
+}
+
+// MulticallResult is the outcome of a single ContractCall
+type MulticallResult struct {
+	// Values are the decoded outputs of a successful call
+	Values []any
+	// Err is set when a call that allows failure could not be encoded, reverted with a *util.ContractRevertError
+	// or returned data that could not be decoded
+	Err error
+}
+
+// MulticallOptions configures Multicall
+type MulticallOptions struct {
+	// BlockTag is the block the calls are executed on, a tag or types.BlockNumber, default is "latest"
+	BlockTag types.BlockTag
+	// Address overrides the Multicall3 address for chains that deployed it elsewhere
+	Address *common.Address
+	// BatchSize limits the calldata bytes packed into one aggregate3 call, default is DefaultMulticallBatchSize.
+	// A single call larger than the limit gets its own chunk
+	BatchSize int
+	// MaxConcurrentChunks limits the chunks in flight at the same time, default is DefaultMulticallConcurrency
+	MaxConcurrentChunks int
+	// StateOverride and BlockOverrides change the state and block context of the calls
+	StateOverride  types.StateOverride
+	BlockOverrides *types.BlockOverrides
+	// Deployless runs the calls through deployless contract code instead of Multicall3, for chains without it
+	Deployless bool
+}
+
+// multicall3Call is an aggregate3 Call3 tuple
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicall3Result is an aggregate3 Result tuple
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// preparedCall is a ContractCall with its encoded calldata
+type preparedCall struct {
+	index int
+	abi   *abi.ABI
+	call  ContractCall
+	data  []byte
+}
+
+// Multicall executes many contract reads with Multicall3 aggregate3 and decodes each result against its ABI.
+// Calls are split into chunks of at most opts.BatchSize calldata bytes of which up to opts.MaxConcurrentChunks are
+// sent concurrently, so batching on the transport coalesces them into few requests. Results are returned in the
+// order of calls.
+// A call that does not allow failure fails the whole multicall when it reverts
+// method: eth_call
+func (c *Client) Multicall(ctx context.Context, calls []ContractCall, opts *MulticallOptions) ([]MulticallResult, error) {
+	if opts == nil {
+		opts = &MulticallOptions{}
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultMulticallBatchSize
+	}
+	concurrency := opts.MaxConcurrentChunks
+	if concurrency <= 0 {
+		concurrency = DefaultMulticallConcurrency
+	}
+
+	results := make([]MulticallResult, len(calls))
+	var (
+		chunks [][]preparedCall
+		chunk  []preparedCall
+		size   int
+	)
+	for i, call := range calls {
+		parsed, err := util.ParseABI(call.ABI)
+		if err != nil {
+			return nil, err
+		}
+		data, err := parsed.Pack(call.FunctionName, call.Args...)
+		if err != nil {
+			err = fmt.Errorf("failed to encode %s call: %w", call.FunctionName, err)
+			if !call.AllowFailure {
+				return nil, err
+			}
+			results[i].Err = err
+			continue
+		}
+		if len(chunk) > 0 && size+len(data) > batchSize {
+			chunks = append(chunks, chunk)
+			chunk, size = nil, 0
+		}
+		chunk = append(chunk, preparedCall{index: i, abi: parsed, call: call, data: data})
+		size += len(data)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		errs      = make([]error, len(chunks))
+		semaphore = make(chan struct{}, concurrency)
+	)
+	for i, chunk := range chunks {
+		semaphore <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			errs[i] = c.multicallChunk(ctx, chunk, opts, results)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// multicallChunk executes one chunk of calls and stores the decoded results
+func (c *Client) multicallChunk(ctx context.Context, chunk []preparedCall, opts *MulticallOptions, results []MulticallResult) error {
+	var (
+		outcomes []multicall3Result
+		err      error
+	)
+	if opts.Deployless {
+		outcomes, err = c.deploylessMulticall(ctx, chunk, opts)
+	} else {
+		outcomes, err = c.aggregate3(ctx, chunk, opts)
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, call := range chunk {
+		result := &results[call.index]
+		outcome := outcomes[i]
+		if !outcome.Success {
+			result.Err = util.DecodeRevert(outcome.ReturnData, call.abi)
+			continue
+		}
+		values, err := call.abi.Unpack(call.call.FunctionName, outcome.ReturnData)
+		if err != nil {
+			result.Err = fmt.Errorf("failed to decode %s result: %w", call.call.FunctionName, err)
+			continue
+		}
+		result.Values = values
+	}
+	return nil
+}
+
+// aggregate3 executes the chunk with the Multicall3 contract
+func (c *Client) aggregate3(ctx context.Context, chunk []preparedCall, opts *MulticallOptions) ([]multicall3Result, error) {
+	multicall, err := util.ParseABI(multicall3ABI)
+	if err != nil {
+		return nil, err
+	}
+	address := Multicall3Address
+	if opts.Address != nil {
+		address = *opts.Address
+	}
+
+	args := make([]multicall3Call, len(chunk))
+	for i, call := range chunk {
+		args[i] = multicall3Call{Target: call.call.Address, AllowFailure: call.call.AllowFailure, CallData: call.data}
+	}
+	data, err := multicall.Pack("aggregate3", args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode aggregate3 call: %w", err)
+	}
+
+	callOpts := overrideOptions(opts.StateOverride, opts.BlockOverrides)
+	output, err := c.Call(ctx, map[string]any{"to": address, "data": hexutil.Bytes(data)}, opts.BlockTag, callOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("multicall: %w", err)
+	}
+	values, err := multicall.Unpack("aggregate3", output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode aggregate3 result: %w", err)
+	}
+	var outcomes []multicall3Result
+	if err := multicall.Methods["aggregate3"].Outputs.Copy(&outcomes, values); err != nil {
+		return nil, fmt.Errorf("failed to decode aggregate3 result: %w", err)
+	}
+	if len(outcomes) != len(chunk) {
+		return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(outcomes), len(chunk))
+	}
+	return outcomes, nil
+}
+
+// deploylessMulticall executes the chunk without a deployed contract, a revert of a call that does not allow failure
+// is decoded against the ABIs of all calls in the chunk
+func (c *Client) deploylessMulticall(ctx context.Context, chunk []preparedCall, opts *MulticallOptions) ([]multicall3Result, error) {
+	calls := make([]deploylessCall, len(chunk))
+	abis := make([]*abi.ABI, len(chunk))
+	for i, call := range chunk {
+		calls[i] = deploylessCall{target: call.call.Address, allowFailure: call.call.AllowFailure, data: call.data}
+		abis[i] = call.abi
+	}
+
+	results, err := c.deploylessBatch(ctx, calls, opts.BlockTag, overrideOptions(opts.StateOverride, opts.BlockOverrides)...)
+	if err != nil {
+		var revertErr *util.ContractRevertError
+		if errors.As(err, &revertErr) && len(revertErr.Data) > 0 {
+			err = util.DecodeRevert(revertErr.Data, abis...)
+		}
+		return nil, fmt.Errorf("multicall: %w", err)
+	}
+	outcomes := make([]multicall3Result, len(results))
+	for i, result := range results {
+		outcomes[i] = multicall3Result{Success: result.success, ReturnData: result.returnData}
+	}
+	return outcomes, nil
+}
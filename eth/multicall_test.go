@@ -0,0 +1,220 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/runtime"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// identityCode returns its calldata without the selector
+	identityCode = hexutil.MustDecode("0x600436036004600037600436036000f3")
+	// reverterCode reverts with its calldata
+	reverterCode = hexutil.MustDecode("0x366000600037366000fd")
+
+	identityAddress = common.HexToAddress("0x1000")
+	reverterAddress = common.HexToAddress("0x2000")
+)
+
+const testContractABI = `
+function identity(uint256 x) view returns (uint256)
+function Fail(uint256 code) view returns (uint256)
+error Fail(uint256 code)
+`
+
+// newEVMClient returns a mock transport answering eth_call by executing the call in an EVM holding the given contracts.
+// Multicall3 is emulated natively, calls without target run as deployless init code. eth_simulateV1 runs the calls
+// of each block one after another on the same state
+func newEVMClient(t *testing.T, contracts map[common.Address][]byte, onCall func(call map[string]any)) *mockClient {
+	t.Helper()
+	newConfig := func() *runtime.Config {
+		statedb, err := state.New(ethTypes.EmptyRootHash, state.NewDatabaseForTesting())
+		if err != nil {
+			t.Fatalf("state.New error: %v", err)
+		}
+		for address, code := range contracts {
+			statedb.SetCode(address, code)
+		}
+		return &runtime.Config{ChainConfig: params.MergedTestChainConfig, State: statedb, GasLimit: 30_000_000}
+	}
+
+	return &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method == types.SimulateV1 {
+				return emulateSimulate(params[0].(types.SimulateParams), newConfig())
+			}
+			if method != types.Call {
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+			call := params[0].(map[string]any)
+			if onCall != nil {
+				onCall(call)
+			}
+			data := call["data"].(hexutil.Bytes)
+			var (
+				output []byte
+				err    error
+			)
+			switch to, _ := call["to"].(common.Address); to {
+			case common.Address{}:
+				output, _, _, err = runtime.Create(data, newConfig())
+			case Multicall3Address:
+				output, err = emulateAggregate3(data, newConfig())
+			default:
+				output, _, err = runtime.Call(to, data, newConfig())
+			}
+			if err != nil {
+				return nil, &testRevertError{data: hexutil.Encode(output)}
+			}
+			return json.RawMessage(fmt.Sprintf(`"%s"`, hexutil.Encode(output))), nil
+		},
+	}
+}
+
+// emulateSimulate implements eth_simulateV1 for calls with a target on top of the EVM
+func emulateSimulate(params types.SimulateParams, cfg *runtime.Config) (json.RawMessage, error) {
+	blocks := make([]*types.SimulatedBlock, len(params.Blocks))
+	for i, block := range params.Blocks {
+		blocks[i] = &types.SimulatedBlock{Calls: make([]types.SimulateCallResult, len(block.Calls))}
+		for j, call := range block.Calls {
+			output, _, err := runtime.Call(call["to"].(common.Address), call["data"].(hexutil.Bytes), cfg)
+			result := types.SimulateCallResult{ReturnData: output, Status: types.ReceiptStatusSuccessful}
+			if err != nil {
+				result.Status, result.Error = types.ReceiptStatusFailed, &types.SimulateCallError{Code: 3, Message: err.Error(), Data: output}
+			}
+			blocks[i].Calls[j] = result
+		}
+	}
+	return json.Marshal(blocks)
+}
+
+// emulateAggregate3 implements Multicall3 aggregate3 on top of the EVM
+func emulateAggregate3(data []byte, cfg *runtime.Config) ([]byte, error) {
+	parsed, _ := util.ParseABI(multicall3ABI)
+	method := parsed.Methods["aggregate3"]
+	values, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	var calls []multicall3Call
+	if err := method.Inputs.Copy(&calls, values); err != nil {
+		return nil, err
+	}
+	results := make([]multicall3Result, len(calls))
+	for i, call := range calls {
+		output, _, err := runtime.Call(call.Target, call.CallData, cfg)
+		if err != nil && !call.AllowFailure {
+			reason, _ := util.ParseABI(`function Error(string)`)
+			revert, _ := reason.Pack("Error", "Multicall3: call failed")
+			return revert, err
+		}
+		results[i] = multicall3Result{Success: err == nil, ReturnData: output}
+	}
+	return method.Outputs.Pack(results)
+}
+
+func TestMulticall(t *testing.T) {
+	for _, deployless := range []bool{false, true} {
+		t.Run(fmt.Sprintf("deployless=%v", deployless), func(t *testing.T) {
+			var requests atomic.Int32
+			pc := &Client{Client: newEVMClient(t, map[common.Address][]byte{
+				identityAddress: identityCode,
+				reverterAddress: reverterCode,
+			}, func(map[string]any) { requests.Add(1) })}
+
+			calls := make([]ContractCall, 0, 10)
+			for i := 0; i < 8; i++ {
+				calls = append(calls, ContractCall{ABI: testContractABI, Address: identityAddress, FunctionName: "identity", Args: []any{big.NewInt(int64(i))}})
+			}
+			calls = append(calls,
+				ContractCall{ABI: testContractABI, Address: reverterAddress, FunctionName: "Fail", Args: []any{big.NewInt(42)}, AllowFailure: true},
+				ContractCall{ABI: testContractABI, Address: identityAddress, FunctionName: "identity", Args: []any{"not a number"}, AllowFailure: true},
+			)
+
+			results, err := pc.Multicall(context.Background(), calls, &MulticallOptions{BatchSize: 36 * 4, Deployless: deployless})
+			if err != nil {
+				t.Fatalf("Multicall error: %v", err)
+			}
+			if n := requests.Load(); n != 3 {
+				t.Errorf("expected 3 chunks, got %d", n)
+			}
+			for i := 0; i < 8; i++ {
+				if results[i].Err != nil || results[i].Values[0].(*big.Int).Int64() != int64(i) {
+					t.Errorf("unexpected result %d: %+v", i, results[i])
+				}
+			}
+			var revertErr *util.ContractRevertError
+			if !errors.As(results[8].Err, &revertErr) || revertErr.Name != "Fail" || revertErr.Args[0].(*big.Int).Int64() != 42 {
+				t.Errorf("unexpected revert result: %+v", results[8])
+			}
+			if results[9].Err == nil {
+				t.Error("expected encoding error result")
+			}
+
+			// a failing call without allowFailure fails the multicall
+			calls[8].AllowFailure = false
+			if _, err := pc.Multicall(context.Background(), calls[:9], &MulticallOptions{Deployless: deployless}); !errors.As(err, &revertErr) {
+				t.Errorf("expected revert error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMulticall_MaxConcurrentChunks(t *testing.T) {
+	evm := newEVMClient(t, map[common.Address][]byte{identityAddress: identityCode}, nil)
+	var inFlight, peak, requests atomic.Int32
+	pc := &Client{Client: &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				if p := peak.Load(); n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			requests.Add(1)
+			time.Sleep(5 * time.Millisecond)
+			return evm.requestFunc(ctx, method, params...)
+		},
+	}}
+
+	calls := make([]ContractCall, 12)
+	for i := range calls {
+		calls[i] = ContractCall{ABI: testContractABI, Address: identityAddress, FunctionName: "identity", Args: []any{big.NewInt(int64(i))}}
+	}
+	results, err := pc.Multicall(context.Background(), calls, &MulticallOptions{BatchSize: 1, MaxConcurrentChunks: 2})
+	if err != nil {
+		t.Fatalf("Multicall error: %v", err)
+	}
+	if requests.Load() != 12 || peak.Load() != 2 {
+		t.Errorf("expected 12 chunks with 2 in flight, got %d chunks with %d in flight", requests.Load(), peak.Load())
+	}
+	for i, result := range results {
+		if result.Err != nil || result.Values[0].(*big.Int).Int64() != int64(i) {
+			t.Errorf("unexpected result %d: %+v", i, result)
+		}
+	}
+}
+
+func TestDeploylessBatch_Revert(t *testing.T) {
+	pc := &Client{Client: newEVMClient(t, map[common.Address][]byte{reverterAddress: reverterCode}, nil)}
+	_, err := pc.deploylessBatch(context.Background(), []deploylessCall{{target: reverterAddress, data: []byte{1, 2, 3}}}, types.LATEST)
+	var revertErr *util.ContractRevertError
+	if !errors.As(err, &revertErr) || hexutil.Encode(revertErr.Data) != "0x010203" {
+		t.Fatalf("expected revert with the call's data, got %v", err)
+	}
+}
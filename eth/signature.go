@@ -0,0 +1,130 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ERC6492MagicSuffix ends signatures of counterfactual accounts wrapped as
+// abi.encode(address factory, bytes factoryCalldata, bytes signature) ++ ERC6492MagicSuffix
+var ERC6492MagicSuffix = common.HexToHash("0x6492649264926492649264926492649264926492649264926492649264926492")
+
+// erc1271MagicValue is returned by isValidSignature for valid signatures
+var erc1271MagicValue = []byte{0x16, 0x26, 0xba, 0x7e}
+
+// erc1271ABI is the ERC-1271 interface of contract accounts
+const erc1271ABI = `function isValidSignature(bytes32 hash, bytes signature) view returns (bytes4 magicValue)`
+
+// erc6492ABI describes the wrapper of ERC-6492 signatures
+const erc6492ABI = `function wrap(address factory, bytes factoryCalldata, bytes signature)`
+
+// VerifyHashOptions configures VerifyHash
+type VerifyHashOptions struct {
+	// BlockTag is the block contract accounts verify the signature on, a tag or types.BlockNumber, default is "latest"
+	BlockTag types.BlockTag
+}
+
+// VerifyHash reports whether the account at address signed the hash. Signatures of externally owned accounts are
+// recovered locally, with v 0, 1, 27 or 28 or in EIP-2098 compact form. Other signatures are checked by calling
+// isValidSignature of ERC-1271 contract accounts, and ERC-6492 signatures of accounts that are not deployed yet
+// simulate the account's factory and the check in one eth_simulateV1 block, which the node must support.
+// Errors are only returned when the node cannot be queried, rejected signatures report false
+// method: eth_call, eth_simulateV1
+func (c *Client) VerifyHash(ctx context.Context, address common.Address, hash common.Hash, signature []byte, opts *VerifyHashOptions) (bool, error) {
+	if opts == nil {
+		opts = &VerifyHashOptions{}
+	}
+	if bytes.HasSuffix(signature, ERC6492MagicSuffix.Bytes()) {
+		return c.verifyERC6492(ctx, address, hash, signature, opts.BlockTag)
+	}
+	if signer, err := util.RecoverAddress(hash, signature); err == nil && signer == address {
+		return true, nil
+	}
+
+	data, err := encodeIsValidSignature(hash, signature)
+	if err != nil {
+		return false, err
+	}
+	output, err := c.Call(ctx, map[string]any{"to": address, "data": hexutil.Bytes(data)}, opts.BlockTag)
+	if err != nil {
+		var revertErr *util.ContractRevertError
+		if errors.As(err, &revertErr) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isERC1271MagicValue(output), nil
+}
+
+// VerifyMessage reports whether the account at address signed the EIP-191 personal message, see VerifyHash
+// method: eth_call
+func (c *Client) VerifyMessage(ctx context.Context, address common.Address, message, signature []byte, opts *VerifyHashOptions) (bool, error) {
+	return c.VerifyHash(ctx, address, util.HashMessage(message), signature, opts)
+}
+
+// VerifyTypedData reports whether the account at address signed the EIP-712 typed data given as JSON, see VerifyHash
+// method: eth_call
+func (c *Client) VerifyTypedData(ctx context.Context, address common.Address, typedDataJSON string, signature []byte, opts *VerifyHashOptions) (bool, error) {
+	hash, err := util.TypedDataHash(typedDataJSON)
+	if err != nil {
+		return false, err
+	}
+	return c.VerifyHash(ctx, address, hash, signature, opts)
+}
+
+// verifyERC6492 unwraps an ERC-6492 signature and simulates the factory call followed by isValidSignature on the
+// account in one block, so the account exists when it is asked. The factory call may fail, as accounts deployed since
+// the signature was created cannot be deployed again
+// method: eth_simulateV1
+func (c *Client) verifyERC6492(ctx context.Context, address common.Address, hash common.Hash, signature []byte, blockTag types.BlockTag) (bool, error) {
+	parsed, err := util.ParseABI(erc6492ABI)
+	if err != nil {
+		return false, err
+	}
+	values, err := parsed.Methods["wrap"].Inputs.Unpack(signature[:len(signature)-common.HashLength])
+	if err != nil {
+		return false, nil
+	}
+	factory, factoryCalldata, inner := values[0].(common.Address), values[1].([]byte), values[2].([]byte)
+
+	data, err := encodeIsValidSignature(hash, inner)
+	if err != nil {
+		return false, err
+	}
+	blocks, err := c.SimulateBlocks(ctx, types.SimulateParams{
+		Blocks: []types.SimulateBlock{{
+			Calls: []map[string]any{
+				{"to": factory, "data": hexutil.Bytes(factoryCalldata)},
+				{"to": address, "data": hexutil.Bytes(data)},
+			},
+		}},
+	}, blockTag)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify ERC-6492 signature: %w", err)
+	}
+	if len(blocks) != 1 || len(blocks[0].Calls) != 2 {
+		return false, fmt.Errorf("failed to verify ERC-6492 signature: expected 2 call results, got %d blocks", len(blocks))
+	}
+	result := blocks[0].Calls[1]
+	return result.Status == types.ReceiptStatusSuccessful && isERC1271MagicValue(result.ReturnData), nil
+}
+
+// encodeIsValidSignature encodes an ERC-1271 isValidSignature call
+func encodeIsValidSignature(hash common.Hash, signature []byte) ([]byte, error) {
+	parsed, err := util.ParseABI(erc1271ABI)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack("isValidSignature", hash, signature)
+}
+
+// isERC1271MagicValue reports whether the output of isValidSignature is the bytes4 magic value
+func isERC1271MagicValue(output []byte) bool {
+	return len(output) >= 32 && bytes.Equal(output[:4], erc1271MagicValue)
+}
@@ -0,0 +1,84 @@
+package eth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// walletCode returns the runtime code of an ERC-1271 account that accepts any signature of hash
+func walletCode(hash common.Hash) []byte {
+	code := hexutil.MustDecode("0x6004357f")
+	code = append(code, hash.Bytes()...)
+	code = append(code, hexutil.MustDecode("0x14602d5760006000f35b7f1626ba7e")...)
+	code = append(code, make([]byte, 28)...)
+	return append(code, hexutil.MustDecode("0x60005260206000f3")...)
+}
+
+// factoryCode returns the code of a factory that deploys the runtime code with CREATE when called
+func factoryCode(runtime []byte) []byte {
+	initCode := append(hexutil.MustDecode("0x6057600c60003960576000f3"), runtime...)
+	return append(hexutil.MustDecode("0x60636010600039606360006000f05000"), initCode...)
+}
+
+func TestVerifyHash(t *testing.T) {
+	hash := crypto.Keccak256Hash([]byte("order 42"))
+	other := crypto.Keccak256Hash([]byte("order 43"))
+	wallet := walletCode(hash)
+	walletAddress := common.HexToAddress("0x3000")
+	factoryAddress := common.HexToAddress("0x4000")
+	counterfactual := crypto.CreateAddress(factoryAddress, 0)
+
+	calls := 0
+	pc := &Client{Client: newEVMClient(t, map[common.Address][]byte{
+		walletAddress:  wallet,
+		factoryAddress: factoryCode(wallet),
+	}, func(map[string]any) { calls++ })}
+	ctx := context.Background()
+
+	key, _ := crypto.GenerateKey()
+	eoa := crypto.PubkeyToAddress(key.PublicKey)
+	signature, _ := crypto.Sign(hash.Bytes(), key)
+	if valid, err := pc.VerifyHash(ctx, eoa, hash, signature, nil); err != nil || !valid || calls != 0 {
+		t.Errorf("expected valid EOA signature without calls, got %v (%v), %d calls", valid, err, calls)
+	}
+	if valid, err := pc.VerifyHash(ctx, eoa, other, signature, nil); err != nil || valid {
+		t.Errorf("expected invalid EOA signature for another hash, got %v (%v)", valid, err)
+	}
+
+	contractSignature := []byte{0x01, 0x02}
+	if valid, err := pc.VerifyHash(ctx, walletAddress, hash, contractSignature, nil); err != nil || !valid {
+		t.Errorf("expected valid ERC-1271 signature, got %v (%v)", valid, err)
+	}
+	if valid, err := pc.VerifyHash(ctx, walletAddress, other, contractSignature, nil); err != nil || valid {
+		t.Errorf("expected invalid ERC-1271 signature for another hash, got %v (%v)", valid, err)
+	}
+
+	parsed, _ := util.ParseABI(erc6492ABI)
+	wrapped, _ := parsed.Methods["wrap"].Inputs.Pack(factoryAddress, []byte{0xaa}, contractSignature)
+	wrapped = append(wrapped, ERC6492MagicSuffix.Bytes()...)
+	if valid, err := pc.VerifyHash(ctx, counterfactual, hash, wrapped, nil); err != nil || !valid {
+		t.Errorf("expected valid ERC-6492 signature, got %v (%v)", valid, err)
+	}
+	if valid, err := pc.VerifyHash(ctx, counterfactual, other, wrapped, nil); err != nil || valid {
+		t.Errorf("expected invalid ERC-6492 signature for another hash, got %v (%v)", valid, err)
+	}
+	// the account is deployed since it signed, its factory call fails
+	if valid, err := pc.VerifyHash(ctx, walletAddress, hash, wrapped, nil); err != nil || !valid {
+		t.Errorf("expected valid ERC-6492 signature of a deployed account, got %v (%v)", valid, err)
+	}
+	if valid, err := pc.VerifyHash(ctx, counterfactual, hash, contractSignature, nil); err != nil || valid {
+		t.Errorf("expected invalid unwrapped signature of an undeployed account, got %v (%v)", valid, err)
+	}
+
+	message := []byte("hello")
+	messageSignature, _ := crypto.Sign(util.HashMessage(message).Bytes(), key)
+	messageSignature[64] += 27
+	if valid, err := pc.VerifyMessage(ctx, eoa, message, messageSignature, nil); err != nil || !valid {
+		t.Errorf("expected valid message signature, got %v (%v)", valid, err)
+	}
+}
@@ -0,0 +1,26 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"github.com/AutoArbi/go-viem/transfer"
+	"github.com/AutoArbi/go-viem/types"
+)
+
+// SimulateBlocks simulates a sequence of blocks on top of blockTag, each with its own block and state overrides
+// and calls executed in order. It returns the simulated blocks with the result of every call, default blockTag is "latest"
+// method: eth_simulateV1
+func (c *Client) SimulateBlocks(ctx context.Context, params types.SimulateParams, blockTag types.BlockTag) ([]*types.SimulatedBlock, error) {
+	if blockTag == "" {
+		blockTag = types.LATEST
+	}
+	res, err := c.Client.Request(ctx, types.SimulateV1, params, blockTag)
+	if err != nil {
+		return nil, err
+	}
+	var blocks []*types.SimulatedBlock
+	if err := transfer.NewRPCResponseTransfer().TransferStruct(res, &blocks); err != nil {
+		return nil, fmt.Errorf("failed to parse simulated blocks: %w", err)
+	}
+	return blocks, nil
+}
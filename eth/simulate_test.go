@@ -0,0 +1,118 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const testSimulateResultJSON = `[{
+	"number": "0x65",
+	"hash": "0x00000000000000000000000000000000000000000000000000000000000000b1",
+	"parentHash": "0x0000000000000000000000000000000000000000000000000000000000000001",
+	"miner": "0x0000000000000000000000000000000000000000",
+	"gasLimit": "0x1c9c380",
+	"gasUsed": "0xb9c0",
+	"timestamp": "0x6553f10c",
+	"baseFeePerGas": "0x0",
+	"size": "0x2a0",
+	"transactions": [
+		"0x00000000000000000000000000000000000000000000000000000000000000c1",
+		"0x00000000000000000000000000000000000000000000000000000000000000c2"
+	],
+	"uncles": [],
+	"withdrawals": [],
+	"calls": [{
+		"returnData": "0x0000000000000000000000000000000000000000000000000000000000000001",
+		"logs": [{
+			"address": "0x0000000000000000000000000000000000000007",
+			"topics": ["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"],
+			"data": "0x",
+			"blockNumber": "0x65",
+			"transactionHash": "0x00000000000000000000000000000000000000000000000000000000000000c1",
+			"transactionIndex": "0x0",
+			"blockHash": "0x00000000000000000000000000000000000000000000000000000000000000b1",
+			"logIndex": "0x0",
+			"removed": false
+		}],
+		"gasUsed": "0x5dc0",
+		"status": "0x1"
+	}, {
+		"returnData": "0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000046f6f707300000000000000000000000000000000000000000000000000000000",
+		"logs": [],
+		"gasUsed": "0x5c00",
+		"status": "0x0",
+		"error": {
+			"message": "execution reverted: oops",
+			"code": 3,
+			"data": "0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000046f6f707300000000000000000000000000000000000000000000000000000000"
+		}
+	}]
+}]`
+
+func TestSimulateBlocks(t *testing.T) {
+	sender := common.HexToAddress("0x6")
+	token := common.HexToAddress("0x7")
+	timestamp := uint64(0x6553f10c)
+	params := types.SimulateParams{
+		Blocks: []types.SimulateBlock{{
+			BlockOverrides: &types.BlockOverrides{Time: &timestamp},
+			StateOverrides: types.StateOverride{sender: {Balance: big.NewInt(1e18)}},
+			Calls: []map[string]any{
+				{"from": sender, "to": token, "data": hexutil.Bytes{0xa9, 0x05, 0x9c, 0xbb}},
+				{"from": sender, "to": token, "data": hexutil.Bytes{0x12, 0x34, 0x56, 0x78}},
+			},
+		}},
+		Validation:     true,
+		TraceTransfers: true,
+	}
+
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.SimulateV1 {
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+			encoded, err := json.Marshal(params)
+			if err != nil {
+				return nil, err
+			}
+			want := `[{"blockStateCalls":[{"blockOverrides":{"time":"0x6553f10c"},` +
+				`"stateOverrides":{"0x0000000000000000000000000000000000000006":{"balance":"0xde0b6b3a7640000"}},` +
+				`"calls":[{"data":"0xa9059cbb","from":"0x0000000000000000000000000000000000000006","to":"0x0000000000000000000000000000000000000007"},` +
+				`{"data":"0x12345678","from":"0x0000000000000000000000000000000000000006","to":"0x0000000000000000000000000000000000000007"}]}],` +
+				`"validation":true,"traceTransfers":true,"returnFullTransactions":false},"latest"]`
+			if string(encoded) != want {
+				return nil, fmt.Errorf("unexpected params\ngot  %s\nwant %s", encoded, want)
+			}
+			return json.RawMessage(testSimulateResultJSON), nil
+		},
+	}
+	pc := &Client{Client: mock}
+
+	blocks, err := pc.SimulateBlocks(context.Background(), params, "")
+	if err != nil {
+		t.Fatalf("SimulateBlocks error: %v", err)
+	}
+	if len(blocks) != 1 || blocks[0].Number.Int64() != 0x65 || len(blocks[0].TransactionHashes) != 2 || len(blocks[0].Calls) != 2 {
+		t.Fatalf("unexpected blocks: %+v", blocks)
+	}
+
+	transfer := blocks[0].Calls[0]
+	if transfer.Status != types.ReceiptStatusSuccessful || transfer.GasUsed != 0x5dc0 || len(transfer.Logs) != 1 || transfer.Error != nil {
+		t.Errorf("unexpected transfer result: %+v", transfer)
+	}
+	failed := blocks[0].Calls[1]
+	if failed.Status != types.ReceiptStatusFailed || failed.Error == nil || failed.Error.Code != 3 {
+		t.Fatalf("unexpected failed result: %+v", failed)
+	}
+	if reason := util.DecodeRevert(failed.Error.Data).Reason; reason != "oops" {
+		t.Errorf("unexpected revert reason %q", reason)
+	}
+}
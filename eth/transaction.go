@@ -2,16 +2,27 @@ package eth
 
 import (
 	"context"
-	"fmt"
 	"github.com/AutoArbi/go-viem/transfer"
 	"github.com/AutoArbi/go-viem/types"
-	"strings"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
-// EstimateGas estimates the gas
+// EstimateGas estimates the gas on the block given by WithBlockTag, default is the node's choice or "latest"
+// when overrides are given
 // method: eth_estimateGas
-func (c *Client) EstimateGas(ctx context.Context, call map[string]any) (uint64, error) {
-	res, err := c.Client.Request(ctx, types.EstimateGas, call)
+func (c *Client) EstimateGas(ctx context.Context, call map[string]any, opts ...CallOption) (uint64, error) {
+	cfg := newCallConfig(opts)
+	params := []any{call}
+	if overrides := cfg.overrideParams(); overrides != nil || cfg.blockTag != "" {
+		blockTag := cfg.blockTag
+		if blockTag == "" {
+			blockTag = types.LATEST
+		}
+		params = append(params, blockTag)
+		params = append(params, overrides...)
+	}
+	res, err := c.Client.Request(ctx, types.EstimateGas, params...)
 	if err != nil {
 		return 0, err
 	}
@@ -19,24 +30,86 @@ func (c *Client) EstimateGas(ctx context.Context, call map[string]any) (uint64,
 	return hexGas.TransferUint64(res)
 }
 
-// SimulateCall simulates an eth_call
-// method: eth_simulateCall
+// SimulateCall executes an eth_call and returns its hex encoded result
+// method: eth_call
+//
+// Deprecated: use Call or ReadContract
 func (c *Client) SimulateCall(ctx context.Context, call map[string]any, blockTag types.BlockTag) (string, error) {
+	result, err := c.Call(ctx, call, blockTag)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(result), nil
+}
+
+// GetTransactionByHash gets a transaction by its hash, errors.ErrNotFound is returned for unknown transactions
+// method: eth_getTransactionByHash
+func (c *Client) GetTransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, error) {
+	res, err := c.Client.Request(ctx, types.GetTransactionByHash, hash.Hex())
+	if err != nil {
+		return nil, err
+	}
+	tx := transfer.NewRPCResponseTransfer()
+	return tx.TransferTransaction(res)
+}
+
+// GetTransactionByBlockHashAndIndex gets the transaction at index of the block with the given hash
+// method: eth_getTransactionByBlockHashAndIndex
+func (c *Client) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index uint64) (*types.Transaction, error) {
+	res, err := c.Client.Request(ctx, types.GetTransactionByBlockHashAndIndex, blockHash.Hex(), hexutil.EncodeUint64(index))
+	if err != nil {
+		return nil, err
+	}
+	tx := transfer.NewRPCResponseTransfer()
+	return tx.TransferTransaction(res)
+}
+
+// GetTransactionByBlockNumberAndIndex gets the transaction at index of a block given by tag or types.BlockNumber, default blockTag is "latest"
+// method: eth_getTransactionByBlockNumberAndIndex
+func (c *Client) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockTag types.BlockTag, index uint64) (*types.Transaction, error) {
 	if blockTag == "" {
 		blockTag = types.LATEST
 	}
-	res, err := c.Client.Request(ctx, types.SimulateCall, call, string(blockTag))
+	res, err := c.Client.Request(ctx, types.GetTransactionByBlockNumberAndIndex, blockTag, hexutil.EncodeUint64(index))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	hexResult := transfer.NewRPCResponseTransfer()
-	result, err := hexResult.TransferString(res)
+	tx := transfer.NewRPCResponseTransfer()
+	return tx.TransferTransaction(res)
+}
+
+// GetTransactionReceipt gets the receipt of a mined transaction, errors.ErrNotFound is returned while it is pending
+// method: eth_getTransactionReceipt
+func (c *Client) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	res, err := c.Client.Request(ctx, types.GetTransactionReceipt, hash.Hex())
 	if err != nil {
-		return "", fmt.Errorf("failed to parse simulation result: %w", err)
+		return nil, err
 	}
+	receipt := transfer.NewRPCResponseTransfer()
+	return receipt.TransferTransactionReceipt(res)
+}
 
-	if !strings.HasPrefix(result, "0x") {
-		return "", fmt.Errorf("invalid hex format result: %s", result)
+// GetBlockReceipts gets all receipts of a block given by tag or types.BlockNumber, default blockTag is "latest"
+// method: eth_getBlockReceipts
+func (c *Client) GetBlockReceipts(ctx context.Context, blockTag types.BlockTag) ([]*types.Receipt, error) {
+	if blockTag == "" {
+		blockTag = types.LATEST
+	}
+	res, err := c.Client.Request(ctx, types.GetBlockReceipts, blockTag)
+	if err != nil {
+		return nil, err
+	}
+	receipts := transfer.NewRPCResponseTransfer()
+	return receipts.TransferTransactionReceipts(res)
+}
+
+// GetBlockReceiptsByHash gets all receipts of the block with the given hash
+// method: eth_getBlockReceipts
+func (c *Client) GetBlockReceiptsByHash(ctx context.Context, blockHash common.Hash) ([]*types.Receipt, error) {
+	res, err := c.Client.Request(ctx, types.GetBlockReceipts, blockHash.Hex())
+	if err != nil {
+		return nil, err
 	}
-	return result, nil
+	receipts := transfer.NewRPCResponseTransfer()
+	return receipts.TransferTransactionReceipts(res)
 }
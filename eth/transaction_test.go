@@ -0,0 +1,81 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetTransactionByHash_NotFound(t *testing.T) {
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetTransactionByHash {
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+			return json.RawMessage("null"), nil
+		},
+	}
+	pc := &Client{Client: mock}
+	_, err := pc.GetTransactionByHash(context.Background(), common.HexToHash("0x1"))
+	if !errors.Is(err, verrors.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestGetTransactionByBlockNumberAndIndex(t *testing.T) {
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetTransactionByBlockNumberAndIndex {
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+			if params[0] != types.BlockTag("0xc8") || params[1] != "0x3" {
+				return nil, fmt.Errorf("unexpected params: %v", params)
+			}
+			return json.RawMessage(`{"hash":"0x00000000000000000000000000000000000000000000000000000000000000aa","blockNumber":"0xc8","transactionIndex":"0x3","type":"0x0","from":"0x0000000000000000000000000000000000000001","to":null,"nonce":"0x7","gas":"0x5208","gasPrice":"0x3b9aca00","value":"0x1","input":"0x"}`), nil
+		},
+	}
+	pc := &Client{Client: mock}
+	tx, err := pc.GetTransactionByBlockNumberAndIndex(context.Background(), types.BlockNumber(big.NewInt(200)), 3)
+	if err != nil {
+		t.Fatalf("GetTransactionByBlockNumberAndIndex error: %v", err)
+	}
+	if tx.Nonce != 7 || tx.To != nil || tx.GasPrice.Int64() != 1e9 || tx.TransactionIndex == nil || *tx.TransactionIndex != 3 {
+		t.Errorf("unexpected transaction: %+v", tx)
+	}
+}
+
+func TestGetBlockReceipts(t *testing.T) {
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetBlockReceipts {
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+			if params[0] != types.FINALIZED {
+				return nil, fmt.Errorf("unexpected params: %v", params)
+			}
+			return json.RawMessage(`[{"transactionHash":"0x00000000000000000000000000000000000000000000000000000000000000aa","blockNumber":"0x1","status":"0x1","gasUsed":"0x5208","effectiveGasPrice":"0x2","contractAddress":null,"logs":[{"address":"0x0000000000000000000000000000000000000002","topics":["0x0000000000000000000000000000000000000000000000000000000000000003"],"data":"0x01","logIndex":"0x4","removed":false}]}]`), nil
+		},
+	}
+	pc := &Client{Client: mock}
+	receipts, err := pc.GetBlockReceipts(context.Background(), types.FINALIZED)
+	if err != nil {
+		t.Fatalf("GetBlockReceipts error: %v", err)
+	}
+	if len(receipts) != 1 {
+		t.Fatalf("expected 1 receipt, got %d", len(receipts))
+	}
+	receipt := receipts[0]
+	if receipt.Status != types.ReceiptStatusSuccessful || receipt.GasUsed != 21000 || len(receipt.Logs) != 1 {
+		t.Errorf("unexpected receipt: %+v", receipt)
+	}
+	if log := receipt.Logs[0]; log.LogIndex == nil || *log.LogIndex != 4 || len(log.Data) != 1 {
+		t.Errorf("unexpected log: %+v", log)
+	}
+}
@@ -0,0 +1,226 @@
+package eth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"time"
+)
+
+// ReplacementReason describes how a transaction was replaced by another one with the same sender and nonce
+type ReplacementReason string
+
+const (
+	// ReplacementRepriced the same call was resent with different fees
+	ReplacementRepriced ReplacementReason = "repriced"
+
+	// ReplacementCancelled the nonce was consumed by a zero value self transfer
+	ReplacementCancelled ReplacementReason = "cancelled"
+
+	// ReplacementReplaced the nonce was consumed by a different transaction
+	ReplacementReplaced ReplacementReason = "replaced"
+)
+
+// TransactionReplacedError is returned by WaitForTransactionReceipt when the awaited transaction was replaced.
+// Receipt is the receipt of the replacement transaction
+type TransactionReplacedError struct {
+	Reason      ReplacementReason
+	Transaction *types.Transaction
+	Replacement *types.Transaction
+	Receipt     *types.Receipt
+}
+
+func (e *TransactionReplacedError) Error() string {
+	return fmt.Sprintf("transaction %s was %s by %s", e.Transaction.Hash.Hex(), e.Reason, e.Replacement.Hash.Hex())
+}
+
+// WaitForTransactionReceiptOptions configures WaitForTransactionReceipt
+type WaitForTransactionReceiptOptions struct {
+	// Confirmations is the number of blocks the receipt must be buried under including its own block, defaults to 1
+	Confirmations uint64
+	// Timeout stops waiting with errors.ErrTimeout, 0 waits until ctx is done
+	Timeout time.Duration
+	// Poll forces polling for new blocks even if the transport supports subscriptions
+	Poll bool
+	// PollingInterval overrides the polling interval of the transport
+	PollingInterval time.Duration
+	// OnReorged is called when the block of an already mined receipt was reorged out, waiting continues afterwards
+	OnReorged func(receipt *types.Receipt)
+}
+
+// WaitForTransactionReceipt waits until the transaction was mined and has the requested confirmations.
+// The receipt is checked on every new block. Once the transaction left the mempool without being mined and
+// the sender's nonce was consumed, the replacing transaction is looked up and a *TransactionReplacedError
+// is returned together with the replacement's confirmed receipt. Replacements are only detected if the
+// transaction was seen by the node at least once while waiting, sender and nonce are unknown otherwise
+func (c *Client) WaitForTransactionReceipt(ctx context.Context, hash common.Hash, opts WaitForTransactionReceiptOptions) (*types.Receipt, error) {
+	if opts.Confirmations == 0 {
+		opts.Confirmations = 1
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := &receiptWaiter{client: c, hash: hash, opts: opts}
+	numbers, _ := c.WatchBlockNumber(ctx, WatchBlockNumberOptions{
+		EmitOnBegin:     true,
+		Poll:            opts.Poll,
+		PollingInterval: opts.PollingInterval,
+	})
+	for number := range numbers {
+		receipt, err := w.check(ctx, number)
+		if err != nil || receipt != nil {
+			return receipt, err
+		}
+	}
+
+	if opts.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("waiting for transaction receipt %s: %w", hash.Hex(), verrors.ErrTimeout)
+	}
+	return nil, ctx.Err()
+}
+
+// receiptWaiter holds the state of a WaitForTransactionReceipt call between blocks
+type receiptWaiter struct {
+	client *Client
+	hash   common.Hash
+	opts   WaitForTransactionReceiptOptions
+
+	// scanFrom is the next block searched for a replacement transaction
+	scanFrom *big.Int
+	tx       *types.Transaction
+	receipt  *types.Receipt
+	replaced *TransactionReplacedError
+}
+
+// check runs on every new block and returns the confirmed receipt or a final error.
+// Request failures are ignored and retried on the next block
+func (w *receiptWaiter) check(ctx context.Context, number *big.Int) (*types.Receipt, error) {
+	if w.scanFrom == nil {
+		w.scanFrom = number
+	}
+
+	if w.replaced != nil {
+		receipt, err := w.confirmed(ctx, w.replaced.Replacement.Hash, number)
+		if err != nil || receipt == nil {
+			return nil, nil
+		}
+		w.replaced.Receipt = receipt
+		return nil, w.replaced
+	}
+
+	receipt, err := w.confirmed(ctx, w.hash, number)
+	if receipt != nil {
+		return receipt, nil
+	}
+	if !errors.Is(err, verrors.ErrNotFound) {
+		return nil, nil
+	}
+
+	tx, err := w.client.GetTransactionByHash(ctx, w.hash)
+	if err == nil {
+		w.tx = tx
+		return nil, nil
+	}
+	if !errors.Is(err, verrors.ErrNotFound) || w.tx == nil {
+		return nil, nil
+	}
+
+	// the transaction left the mempool, look for the transaction that consumed its nonce
+	nonce, err := w.client.GetTransactionCount(ctx, w.tx.From, types.BlockNumber(number))
+	if err != nil || nonce <= w.tx.Nonce {
+		return nil, nil
+	}
+	replacement, err := w.findReplacement(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if replacement == nil {
+		return nil, nil
+	}
+	w.replaced = &TransactionReplacedError{
+		Reason:      replacementReason(w.tx, replacement),
+		Transaction: w.tx,
+		Replacement: replacement,
+	}
+	return w.check(ctx, number)
+}
+
+// confirmed returns the receipt of hash once it has enough confirmations and its block is still canonical.
+// It returns a nil receipt while confirmations are missing or after a reorg and errors.ErrNotFound while the
+// transaction is not mined
+func (w *receiptWaiter) confirmed(ctx context.Context, hash common.Hash, number *big.Int) (*types.Receipt, error) {
+	if w.receipt == nil || w.receipt.TransactionHash != hash {
+		receipt, err := w.client.GetTransactionReceipt(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		w.receipt = receipt
+	}
+
+	confirmations := new(big.Int).Sub(number, w.receipt.BlockNumber)
+	if confirmations.Add(confirmations, big.NewInt(1)).Cmp(new(big.Int).SetUint64(w.opts.Confirmations)) < 0 {
+		return nil, nil
+	}
+	block, err := w.client.GetBlockByNumber(ctx, w.receipt.BlockNumber, false)
+	if err != nil {
+		return nil, err
+	}
+	if block.Hash != w.receipt.BlockHash {
+		removed := w.receipt
+		w.receipt = nil
+		if w.opts.OnReorged != nil {
+			w.opts.OnReorged(removed)
+		}
+		// the receipt is fetched again on the next block, nodes behind a load balancer may disagree until then
+		return nil, nil
+	}
+	return w.receipt, nil
+}
+
+// findReplacement searches the blocks since waiting started for a transaction with the sender and nonce of w.tx.
+// Blocks are scanned once, w.scanFrom is moved past every block that has been searched
+func (w *receiptWaiter) findReplacement(ctx context.Context, number *big.Int) (*types.Transaction, error) {
+	for w.scanFrom.Cmp(number) <= 0 {
+		block, err := w.client.GetBlockByNumber(ctx, w.scanFrom, true)
+		if err != nil {
+			return nil, nil
+		}
+		for _, tx := range block.Transactions {
+			if tx.From == w.tx.From && tx.Nonce == w.tx.Nonce {
+				return tx, nil
+			}
+		}
+		w.scanFrom = new(big.Int).Add(w.scanFrom, big.NewInt(1))
+	}
+	return nil, fmt.Errorf("nonce %d of %s consumed by an unknown transaction: %w", w.tx.Nonce, w.tx.From.Hex(), verrors.ErrTransactionDropped)
+}
+
+// replacementReason classifies a replacement the way viem does
+func replacementReason(tx, replacement *types.Transaction) ReplacementReason {
+	sameValue := func(a, b *big.Int) bool {
+		if a == nil || b == nil {
+			return a == b
+		}
+		return a.Cmp(b) == 0
+	}
+	sameTo := (tx.To == nil && replacement.To == nil) || (tx.To != nil && replacement.To != nil && *tx.To == *replacement.To)
+
+	switch {
+	case sameTo && sameValue(tx.Value, replacement.Value) && bytes.Equal(tx.Input, replacement.Input):
+		return ReplacementRepriced
+	case replacement.To != nil && *replacement.To == replacement.From && (replacement.Value == nil || replacement.Value.Sign() == 0):
+		return ReplacementCancelled
+	default:
+		return ReplacementReplaced
+	}
+}
@@ -0,0 +1,260 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	waitTestTx          = "0x00000000000000000000000000000000000000000000000000000000000000aa"
+	waitTestReplacement = "0x00000000000000000000000000000000000000000000000000000000000000bb"
+)
+
+func waitTestTransaction(hash, fees string) string {
+	return `{"hash":"` + hash + `","type":"0x2","from":"0x0000000000000000000000000000000000000001","to":"0x0000000000000000000000000000000000000002","nonce":"0x5","gas":"0x5208","value":"0x1","input":"0x1234","maxFeePerGas":"` + fees + `","maxPriorityFeePerGas":"` + fees + `"}`
+}
+
+func TestWaitForTransactionReceipt_Repriced(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		head = 10
+	)
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch method {
+			case types.GetBlockNumber:
+				head++
+				return json.RawMessage(fmt.Sprintf(`"0x%x"`, head)), nil
+			case types.GetTransactionReceipt:
+				if params[0] == waitTestReplacement && head >= 12 {
+					return json.RawMessage(`{"transactionHash":"` + waitTestReplacement + `","blockNumber":"0xc","blockHash":"0x0000000000000000000000000000000000000000000000000000000000000c00","status":"0x1"}`), nil
+				}
+				return json.RawMessage("null"), nil
+			case types.GetTransactionByHash:
+				if head < 12 {
+					return json.RawMessage(waitTestTransaction(waitTestTx, "0x1")), nil
+				}
+				return json.RawMessage("null"), nil
+			case types.GetTransactionCount:
+				return json.RawMessage(`"0x6"`), nil
+			case types.GetBlockByNumber:
+				if params[0] != "0xc" {
+					return json.RawMessage(`{"number":"` + params[0].(string) + `","transactions":[]}`), nil
+				}
+				if params[1] != true {
+					return json.RawMessage(`{"number":"0xc","hash":"0x0000000000000000000000000000000000000000000000000000000000000c00","transactions":[]}`), nil
+				}
+				return json.RawMessage(`{"number":"0xc","hash":"0x0000000000000000000000000000000000000000000000000000000000000c00","transactions":[` + waitTestTransaction(waitTestReplacement, "0x2") + `]}`), nil
+			default:
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+		},
+	}
+	pc := &Client{Client: mock}
+
+	_, err := pc.WaitForTransactionReceipt(context.Background(), common.HexToHash(waitTestTx), WaitForTransactionReceiptOptions{
+		PollingInterval: 5 * time.Millisecond,
+		Timeout:         2 * time.Second,
+	})
+	var replaced *TransactionReplacedError
+	if !errors.As(err, &replaced) {
+		t.Fatalf("expected TransactionReplacedError, got %v", err)
+	}
+	if replaced.Reason != ReplacementRepriced {
+		t.Errorf("expected reason repriced, got %s", replaced.Reason)
+	}
+	if replaced.Receipt == nil || replaced.Receipt.TransactionHash != common.HexToHash(waitTestReplacement) {
+		t.Errorf("expected replacement receipt, got %+v", replaced.Receipt)
+	}
+}
+
+func TestWaitForTransactionReceipt_Reorged(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		head     = 10
+		receipts = []string{"0x0000000000000000000000000000000000000000000000000000000000000a01", "0x0000000000000000000000000000000000000000000000000000000000000a02"}
+	)
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch method {
+			case types.GetBlockNumber:
+				head++
+				return json.RawMessage(fmt.Sprintf(`"0x%x"`, head)), nil
+			case types.GetTransactionReceipt:
+				blockHash := receipts[0]
+				if len(receipts) > 1 {
+					receipts = receipts[1:]
+				}
+				return json.RawMessage(`{"transactionHash":"` + waitTestTx + `","blockNumber":"0xb","blockHash":"` + blockHash + `","status":"0x1"}`), nil
+			case types.GetBlockByNumber:
+				return json.RawMessage(`{"number":"0xb","hash":"0x0000000000000000000000000000000000000000000000000000000000000a02","transactions":[]}`), nil
+			default:
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+		},
+	}
+	pc := &Client{Client: mock}
+
+	var reorged []*types.Receipt
+	receipt, err := pc.WaitForTransactionReceipt(context.Background(), common.HexToHash(waitTestTx), WaitForTransactionReceiptOptions{
+		Confirmations:   3,
+		PollingInterval: 5 * time.Millisecond,
+		Timeout:         2 * time.Second,
+		OnReorged: func(receipt *types.Receipt) {
+			reorged = append(reorged, receipt)
+		},
+	})
+	if err != nil {
+		t.Fatalf("WaitForTransactionReceipt error: %v", err)
+	}
+	if receipt.BlockHash != common.HexToHash("0xa02") {
+		t.Errorf("expected receipt of the canonical block, got %s", receipt.BlockHash.Hex())
+	}
+	if len(reorged) != 1 || reorged[0].BlockHash != common.HexToHash("0xa01") {
+		t.Errorf("expected one reorged receipt, got %v", reorged)
+	}
+}
+
+func TestWaitForTransactionReceipt_DisagreeingBackends(t *testing.T) {
+	var blocks int
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			switch method {
+			case types.GetBlockNumber:
+				return json.RawMessage(`"0xd"`), nil
+			case types.GetTransactionReceipt:
+				return json.RawMessage(`{"transactionHash":"` + waitTestTx + `","blockNumber":"0xb","blockHash":"0x0000000000000000000000000000000000000000000000000000000000000a01","status":"0x1"}`), nil
+			case types.GetBlockByNumber:
+				// another backend never saw the receipt's block
+				blocks++
+				return json.RawMessage(`{"number":"0xb","hash":"0x0000000000000000000000000000000000000000000000000000000000000a02","transactions":[]}`), nil
+			default:
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+		},
+	}
+	pc := &Client{Client: mock}
+
+	reorged := 0
+	_, err := pc.WaitForTransactionReceipt(context.Background(), common.HexToHash(waitTestTx), WaitForTransactionReceiptOptions{
+		Confirmations:   3,
+		PollingInterval: 10 * time.Millisecond,
+		Timeout:         100 * time.Millisecond,
+		OnReorged:       func(*types.Receipt) { reorged++ },
+	})
+	if err == nil {
+		t.Fatal("expected timeout while the backends disagree")
+	}
+	if reorged == 0 || reorged != blocks || reorged > 20 {
+		t.Errorf("expected one reorg per poll, got %d reorgs for %d block requests", reorged, blocks)
+	}
+}
+
+func TestWaitForTransactionReceipt_ReorgedSingleConfirmation(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		head     = 10
+		receipts = []string{"0x0000000000000000000000000000000000000000000000000000000000000a01", "0x0000000000000000000000000000000000000000000000000000000000000a02"}
+	)
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch method {
+			case types.GetBlockNumber:
+				head++
+				return json.RawMessage(fmt.Sprintf(`"0x%x"`, head)), nil
+			case types.GetTransactionReceipt:
+				blockHash := receipts[0]
+				if len(receipts) > 1 {
+					receipts = receipts[1:]
+				}
+				return json.RawMessage(`{"transactionHash":"` + waitTestTx + `","blockNumber":"0xb","blockHash":"` + blockHash + `","status":"0x1"}`), nil
+			case types.GetBlockByNumber:
+				return json.RawMessage(`{"number":"0xb","hash":"0x0000000000000000000000000000000000000000000000000000000000000a02","transactions":[]}`), nil
+			default:
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+		},
+	}
+	pc := &Client{Client: mock}
+
+	reorged := 0
+	receipt, err := pc.WaitForTransactionReceipt(context.Background(), common.HexToHash(waitTestTx), WaitForTransactionReceiptOptions{
+		PollingInterval: 5 * time.Millisecond,
+		Timeout:         2 * time.Second,
+		OnReorged:       func(*types.Receipt) { reorged++ },
+	})
+	if err != nil {
+		t.Fatalf("WaitForTransactionReceipt error: %v", err)
+	}
+	if receipt.BlockHash != common.HexToHash("0xa02") {
+		t.Errorf("expected receipt of the canonical block, got %s", receipt.BlockHash.Hex())
+	}
+	if reorged != 1 {
+		t.Errorf("expected one reorged receipt, got %d", reorged)
+	}
+}
+
+func TestWaitForTransactionReceipt_ScansBlocksOnce(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		head    = 10
+		scanned = map[string]int{}
+	)
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			switch method {
+			case types.GetBlockNumber:
+				head++
+				return json.RawMessage(fmt.Sprintf(`"0x%x"`, head)), nil
+			case types.GetTransactionReceipt:
+				return json.RawMessage("null"), nil
+			case types.GetTransactionByHash:
+				if head < 12 {
+					return json.RawMessage(waitTestTransaction(waitTestTx, "0x1")), nil
+				}
+				return json.RawMessage("null"), nil
+			case types.GetTransactionCount:
+				return json.RawMessage(`"0x6"`), nil
+			case types.GetBlockByNumber:
+				number := params[0].(string)
+				scanned[number]++
+				// the node does not know the head block yet on the first request
+				if scanned[number] == 1 && number == "0xc" {
+					return nil, errors.New("header not found")
+				}
+				return json.RawMessage(`{"number":"` + number + `","transactions":[]}`), nil
+			default:
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+		},
+	}
+	pc := &Client{Client: mock}
+
+	_, err := pc.WaitForTransactionReceipt(context.Background(), common.HexToHash(waitTestTx), WaitForTransactionReceiptOptions{
+		PollingInterval: 5 * time.Millisecond,
+		Timeout:         2 * time.Second,
+	})
+	if !errors.Is(err, verrors.ErrTransactionDropped) {
+		t.Fatalf("expected ErrTransactionDropped, got %v", err)
+	}
+	if scanned["0xb"] != 1 {
+		t.Errorf("expected block 0xb to be scanned once, got %d", scanned["0xb"])
+	}
+}
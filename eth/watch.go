@@ -0,0 +1,221 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"github.com/AutoArbi/go-viem/client"
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"time"
+)
+
+// DefaultPollingInterval is used by watchers when the transport does not provide its own polling interval
+const DefaultPollingInterval = 4 * time.Second
+
+// WatchBlockNumberOptions configures WatchBlockNumber
+type WatchBlockNumberOptions struct {
+	// EmitMissed emits every block number skipped between two updates
+	EmitMissed bool
+	// EmitOnBegin emits the current block number right away instead of waiting for the next one
+	EmitOnBegin bool
+	// Poll forces polling even if the transport supports subscriptions
+	Poll bool
+	// PollingInterval overrides the polling interval of the transport
+	PollingInterval time.Duration
+}
+
+// WatchBlocksOptions configures WatchBlocks
+type WatchBlocksOptions struct {
+	EmitMissed  bool
+	EmitOnBegin bool
+	Poll        bool
+	// IncludeTransactions fetches blocks with full transaction objects instead of hashes
+	IncludeTransactions bool
+	PollingInterval     time.Duration
+}
+
+// pollingIntervalProvider is implemented by transports with a configured polling interval, like client.Client
+type pollingIntervalProvider interface {
+	PollingInterval() time.Duration
+}
+
+// WatchBlockNumber delivers every new block number on the returned channel until ctx is done.
+// It subscribes to newHeads when the transport supports subscriptions and polls eth_blockNumber otherwise.
+// Errors don't stop the watcher, they are delivered on the error channel and dropped if nobody reads them
+func (c *Client) WatchBlockNumber(ctx context.Context, opts WatchBlockNumberOptions) (<-chan *big.Int, <-chan error) {
+	numbers := make(chan *big.Int)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(numbers)
+		defer close(errs)
+
+		var prev *big.Int
+		emit := func(number *big.Int) bool {
+			if prev != nil {
+				if number.Cmp(prev) <= 0 {
+					return true
+				}
+				if opts.EmitMissed {
+					for missed := new(big.Int).Add(prev, big.NewInt(1)); missed.Cmp(number) < 0; missed = new(big.Int).Add(missed, big.NewInt(1)) {
+						if !sendValue(ctx, numbers, missed) {
+							return false
+						}
+					}
+				}
+			}
+			prev = number
+			return sendValue(ctx, numbers, number)
+		}
+
+		if opts.EmitOnBegin {
+			number, err := c.GetBlockNumber(ctx)
+			if err != nil {
+				sendError(errs, err)
+			} else if !emit(number) {
+				return
+			}
+		}
+
+		if !opts.Poll {
+			if ok := c.watchHeads(ctx, emit, errs); ok {
+				return
+			}
+		}
+		c.pollBlockNumber(ctx, opts.PollingInterval, emit, errs)
+	}()
+
+	return numbers, errs
+}
+
+// watchHeads emits the numbers of newHeads notifications. It returns false when the caller should fall back to polling
+func (c *Client) watchHeads(ctx context.Context, emit func(*big.Int) bool, errs chan<- error) bool {
+	subscriber, ok := c.Client.(client.Subscriber)
+	if !ok {
+		return false
+	}
+	heads := make(chan *types.Header)
+	sub, err := client.SubscribeNewHeads(ctx, subscriber, heads)
+	if err != nil {
+		if !errors.Is(err, verrors.ErrSubscriptionNotSupported) && !errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			sendError(errs, err)
+		}
+		return false
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true
+		case err := <-sub.Err():
+			sendError(errs, err)
+			return ctx.Err() != nil
+		case head := <-heads:
+			if !emit(head.Number) {
+				return true
+			}
+		}
+	}
+}
+
+// pollBlockNumber emits the result of eth_blockNumber every polling interval
+func (c *Client) pollBlockNumber(ctx context.Context, interval time.Duration, emit func(*big.Int) bool, errs chan<- error) {
+	ticker := time.NewTicker(c.pollingInterval(interval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			number, err := c.GetBlockNumber(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				sendError(errs, err)
+				continue
+			}
+			if !emit(number) {
+				return
+			}
+		}
+	}
+}
+
+// WatchBlocks delivers every new block on the returned channel until ctx is done, see WatchBlockNumber
+func (c *Client) WatchBlocks(ctx context.Context, opts WatchBlocksOptions) (<-chan *types.Block, <-chan error) {
+	blocks := make(chan *types.Block)
+	errs := make(chan error, 1)
+
+	numbers, numberErrs := c.WatchBlockNumber(ctx, WatchBlockNumberOptions{
+		EmitMissed:      opts.EmitMissed,
+		EmitOnBegin:     opts.EmitOnBegin,
+		Poll:            opts.Poll,
+		PollingInterval: opts.PollingInterval,
+	})
+
+	go func() {
+		defer close(blocks)
+		defer close(errs)
+
+		for numbers != nil || numberErrs != nil {
+			select {
+			case err, ok := <-numberErrs:
+				if !ok {
+					numberErrs = nil
+					continue
+				}
+				sendError(errs, err)
+			case number, ok := <-numbers:
+				if !ok {
+					numbers = nil
+					continue
+				}
+				block, err := c.GetBlockByNumber(ctx, number, opts.IncludeTransactions)
+				if err != nil {
+					if ctx.Err() == nil {
+						sendError(errs, err)
+					}
+					continue
+				}
+				if !sendValue(ctx, blocks, block) {
+					return
+				}
+			}
+		}
+	}()
+
+	return blocks, errs
+}
+
+func (c *Client) pollingInterval(interval time.Duration) time.Duration {
+	if interval > 0 {
+		return interval
+	}
+	if p, ok := c.Client.(pollingIntervalProvider); ok && p.PollingInterval() > 0 {
+		return p.PollingInterval()
+	}
+	return DefaultPollingInterval
+}
+
+// sendValue blocks until v was received or ctx is done
+func sendValue[T any](ctx context.Context, ch chan<- T, v T) bool {
+	select {
+	case ch <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sendError delivers err without blocking, errors are dropped when nobody reads them
+func sendError(errs chan<- error, err error) {
+	select {
+	case errs <- err:
+	default:
+	}
+}
@@ -0,0 +1,98 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AutoArbi/go-viem/types"
+)
+
+func TestWatchBlockNumber_PollEmitMissed(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		results = []string{"0x1", "0x1", "0x4", "0x3", "0x5"}
+	)
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			if method != types.GetBlockNumber {
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			res := results[0]
+			if len(results) > 1 {
+				results = results[1:]
+			}
+			return json.RawMessage(fmt.Sprintf("%q", res)), nil
+		},
+	}
+	pc := &Client{Client: mock}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	numbers, _ := pc.WatchBlockNumber(ctx, WatchBlockNumberOptions{
+		EmitMissed:      true,
+		EmitOnBegin:     true,
+		Poll:            true,
+		PollingInterval: 5 * time.Millisecond,
+	})
+
+	for want := int64(1); want <= 5; want++ {
+		select {
+		case number := <-numbers:
+			if number.Int64() != want {
+				t.Fatalf("expected block number %d, got %s", want, number)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for block number %d", want)
+		}
+	}
+	cancel()
+	for range numbers {
+	}
+}
+
+func TestWatchBlocks_IncludeTransactions(t *testing.T) {
+	mock := &mockClient{
+		requestFunc: func(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error) {
+			switch method {
+			case types.GetBlockNumber:
+				return json.RawMessage(`"0x10"`), nil
+			case types.GetBlockByNumber:
+				if params[0] != "0x10" || params[1] != true {
+					return nil, fmt.Errorf("unexpected params: %v", params)
+				}
+				return json.RawMessage(`{"number":"0x10","hash":"0x0000000000000000000000000000000000000000000000000000000000000abc","gasLimit":"0x1c9c380","gasUsed":"0x0","timestamp":"0x64","blobGasUsed":"0x20000","transactions":[]}`), nil
+			default:
+				return nil, fmt.Errorf("unexpected method: %s", method)
+			}
+		},
+	}
+	pc := &Client{Client: mock}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	blocks, errs := pc.WatchBlocks(ctx, WatchBlocksOptions{
+		EmitOnBegin:         true,
+		IncludeTransactions: true,
+		PollingInterval:     time.Hour,
+	})
+
+	select {
+	case block := <-blocks:
+		if block.Number.Int64() != 16 || block.Timestamp != 100 || block.GasLimit != 30000000 {
+			t.Errorf("unexpected block: %+v", block.Header)
+		}
+		if block.BlobGasUsed == nil || *block.BlobGasUsed != 0x20000 {
+			t.Errorf("expected blobGasUsed 0x20000, got %v", block.BlobGasUsed)
+		}
+	case err := <-errs:
+		t.Fatalf("WatchBlocks error: %v", err)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for block")
+	}
+}
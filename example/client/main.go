@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/AutoArbi/go-viem/client"
+	"github.com/AutoArbi/go-viem/types"
 )
 
 func main() {
@@ -24,7 +25,7 @@ func main() {
 	defer cancel()
 
 	// 发送JSON-RPC请求
-	method := "getBlockByNumber"
+	method := types.GetBlockNumber
 	var params []interface{}
 	result, err := cli.Request(ctx, method, params...)
 	if err != nil {
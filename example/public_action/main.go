@@ -43,7 +43,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to get block by number: %v", err)
 	}
-	fmt.Printf("Block By Number: %s\n", blockByNumber)
+	fmt.Printf("Block By Number: %s, %d transactions\n", blockByNumber.Hash, len(blockByNumber.Transactions))
 
 	log2.Trace("Block By Number", blockByNumber)
 
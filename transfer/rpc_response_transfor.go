@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"math/big"
 	"reflect"
 	"strings"
@@ -74,6 +77,15 @@ func (p *RPCResponseTransfer) TransferString(response json.RawMessage) (string,
 	return result, nil
 }
 
+// TransferBytes parses a hex encoded data response, "0x" is returned as empty data
+func (p *RPCResponseTransfer) TransferBytes(response json.RawMessage) ([]byte, error) {
+	var result hexutil.Bytes
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse bytes response [raw: %s]: %w", string(response), err)
+	}
+	return result, nil
+}
+
 // TransferBool parses a JSON-RPC response containing a boolean value.
 func (p *RPCResponseTransfer) TransferBool(response json.RawMessage) (bool, error) {
 	var result bool
@@ -94,8 +106,11 @@ func (p *RPCResponseTransfer) TransferAddress(response json.RawMessage) (common.
 	return address, nil
 }
 
-// TransferTransactionReceipt parses a JSON-RPC response into a transaction.
+// TransferTransactionReceipt parses a JSON-RPC response into a transaction receipt.
 func (p *RPCResponseTransfer) TransferTransactionReceipt(response json.RawMessage) (*types.Receipt, error) {
+	if isNull(response) {
+		return nil, verrors.ErrNotFound
+	}
 	var receipt types.Receipt
 	if err := json.Unmarshal(response, &receipt); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal transaction receipt response: %w", err)
@@ -103,8 +118,35 @@ func (p *RPCResponseTransfer) TransferTransactionReceipt(response json.RawMessag
 	return &receipt, nil
 }
 
+// TransferTransactionReceipts parses a JSON-RPC response into a list of transaction receipts.
+func (p *RPCResponseTransfer) TransferTransactionReceipts(response json.RawMessage) ([]*types.Receipt, error) {
+	if isNull(response) {
+		return nil, verrors.ErrNotFound
+	}
+	var receipts []*types.Receipt
+	if err := json.Unmarshal(response, &receipts); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction receipts response: %w", err)
+	}
+	return receipts, nil
+}
+
+// TransferTransaction parses a JSON-RPC response into a transaction.
+func (p *RPCResponseTransfer) TransferTransaction(response json.RawMessage) (*types.Transaction, error) {
+	if isNull(response) {
+		return nil, verrors.ErrNotFound
+	}
+	var tx types.Transaction
+	if err := json.Unmarshal(response, &tx); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction response: %w", err)
+	}
+	return &tx, nil
+}
+
 // TransferBlock parses a JSON-RPC response into a block.
 func (p *RPCResponseTransfer) TransferBlock(response json.RawMessage) (*types.Block, error) {
+	if isNull(response) {
+		return nil, verrors.ErrNotFound
+	}
 	var block types.Block
 	if err := json.Unmarshal(response, &block); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal block response: %w", err)
@@ -112,6 +154,11 @@ func (p *RPCResponseTransfer) TransferBlock(response json.RawMessage) (*types.Bl
 	return &block, nil
 }
 
+// isNull reports whether the response is empty or JSON null
+func isNull(response json.RawMessage) bool {
+	return len(response) == 0 || bytes.Equal(response, []byte("null"))
+}
+
 // TransferStruct parses a JSON-RPC response into a specified struct.
 func (p *RPCResponseTransfer) TransferStruct(response json.RawMessage, result interface{}) error {
 	if err := json.Unmarshal(response, result); err == nil {
@@ -170,12 +217,12 @@ func (p *RPCResponseTransfer) transferWithCustomTypes(data json.RawMessage, out
 			}
 			fieldValue.Set(reflect.ValueOf(bi))
 
-		case types.Bloom:
+		case ethTypes.Bloom:
 			var s string
 			if err := json.Unmarshal(rawValue, &s); err != nil {
 				return err
 			}
-			fieldValue.Set(reflect.ValueOf(types.BytesToBloom(common.FromHex(s))))
+			fieldValue.Set(reflect.ValueOf(ethTypes.BytesToBloom(common.FromHex(s))))
 
 		default:
 			if err := json.Unmarshal(rawValue, fieldValue.Addr().Interface()); err != nil {
@@ -0,0 +1,264 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// Header is a block header as returned by the JSON-RPC API
+type Header struct {
+	Hash             common.Hash
+	ParentHash       common.Hash
+	UncleHash        common.Hash
+	Miner            common.Address
+	StateRoot        common.Hash
+	TransactionsRoot common.Hash
+	ReceiptsRoot     common.Hash
+	LogsBloom        ethTypes.Bloom
+	Difficulty       *big.Int
+	Number           *big.Int
+	GasLimit         uint64
+	GasUsed          uint64
+	Timestamp        uint64
+	ExtraData        []byte
+	MixHash          common.Hash
+	Nonce            ethTypes.BlockNonce
+
+	// BaseFeePerGas was added by EIP-1559 and is nil for legacy blocks
+	BaseFeePerGas *big.Int
+
+	// WithdrawalsRoot was added by EIP-4895 and is nil before Shanghai
+	WithdrawalsRoot *common.Hash
+
+	// BlobGasUsed and ExcessBlobGas were added by EIP-4844 and are nil before Cancun
+	BlobGasUsed   *uint64
+	ExcessBlobGas *uint64
+
+	// ParentBeaconBlockRoot was added by EIP-4788 and is nil before Cancun
+	ParentBeaconBlockRoot *common.Hash
+
+	// RequestsHash was added by EIP-7685 and is nil before Prague
+	RequestsHash *common.Hash
+}
+
+type headerJSON struct {
+	Hash                  *common.Hash         `json:"hash"`
+	ParentHash            common.Hash          `json:"parentHash"`
+	UncleHash             common.Hash          `json:"sha3Uncles"`
+	Miner                 *common.Address      `json:"miner"`
+	StateRoot             common.Hash          `json:"stateRoot"`
+	TransactionsRoot      common.Hash          `json:"transactionsRoot"`
+	ReceiptsRoot          common.Hash          `json:"receiptsRoot"`
+	LogsBloom             *ethTypes.Bloom      `json:"logsBloom"`
+	Difficulty            *hexutil.Big         `json:"difficulty"`
+	Number                *hexutil.Big         `json:"number"`
+	GasLimit              hexutil.Uint64       `json:"gasLimit"`
+	GasUsed               hexutil.Uint64       `json:"gasUsed"`
+	Timestamp             hexutil.Uint64       `json:"timestamp"`
+	ExtraData             hexutil.Bytes        `json:"extraData"`
+	MixHash               *common.Hash         `json:"mixHash"`
+	Nonce                 *ethTypes.BlockNonce `json:"nonce"`
+	BaseFeePerGas         *hexutil.Big         `json:"baseFeePerGas,omitempty"`
+	WithdrawalsRoot       *common.Hash         `json:"withdrawalsRoot,omitempty"`
+	BlobGasUsed           *hexutil.Uint64      `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas         *hexutil.Uint64      `json:"excessBlobGas,omitempty"`
+	ParentBeaconBlockRoot *common.Hash         `json:"parentBeaconBlockRoot,omitempty"`
+	RequestsHash          *common.Hash         `json:"requestsHash,omitempty"`
+}
+
+// MarshalJSON encodes the header in the JSON-RPC format
+func (h Header) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.toJSON())
+}
+
+// UnmarshalJSON decodes a JSON-RPC header, pending headers may have a null hash, miner and nonce
+func (h *Header) UnmarshalJSON(input []byte) error {
+	var dec headerJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	h.fromJSON(&dec)
+	return nil
+}
+
+func (h *Header) toJSON() *headerJSON {
+	return &headerJSON{
+		Hash:                  &h.Hash,
+		ParentHash:            h.ParentHash,
+		UncleHash:             h.UncleHash,
+		Miner:                 &h.Miner,
+		StateRoot:             h.StateRoot,
+		TransactionsRoot:      h.TransactionsRoot,
+		ReceiptsRoot:          h.ReceiptsRoot,
+		LogsBloom:             &h.LogsBloom,
+		Difficulty:            (*hexutil.Big)(h.Difficulty),
+		Number:                (*hexutil.Big)(h.Number),
+		GasLimit:              hexutil.Uint64(h.GasLimit),
+		GasUsed:               hexutil.Uint64(h.GasUsed),
+		Timestamp:             hexutil.Uint64(h.Timestamp),
+		ExtraData:             h.ExtraData,
+		MixHash:               &h.MixHash,
+		Nonce:                 &h.Nonce,
+		BaseFeePerGas:         (*hexutil.Big)(h.BaseFeePerGas),
+		WithdrawalsRoot:       h.WithdrawalsRoot,
+		BlobGasUsed:           (*hexutil.Uint64)(h.BlobGasUsed),
+		ExcessBlobGas:         (*hexutil.Uint64)(h.ExcessBlobGas),
+		ParentBeaconBlockRoot: h.ParentBeaconBlockRoot,
+		RequestsHash:          h.RequestsHash,
+	}
+}
+
+func (h *Header) fromJSON(dec *headerJSON) {
+	*h = Header{
+		ParentHash:            dec.ParentHash,
+		UncleHash:             dec.UncleHash,
+		StateRoot:             dec.StateRoot,
+		TransactionsRoot:      dec.TransactionsRoot,
+		ReceiptsRoot:          dec.ReceiptsRoot,
+		Difficulty:            (*big.Int)(dec.Difficulty),
+		Number:                (*big.Int)(dec.Number),
+		GasLimit:              uint64(dec.GasLimit),
+		GasUsed:               uint64(dec.GasUsed),
+		Timestamp:             uint64(dec.Timestamp),
+		ExtraData:             dec.ExtraData,
+		BaseFeePerGas:         (*big.Int)(dec.BaseFeePerGas),
+		WithdrawalsRoot:       dec.WithdrawalsRoot,
+		BlobGasUsed:           (*uint64)(dec.BlobGasUsed),
+		ExcessBlobGas:         (*uint64)(dec.ExcessBlobGas),
+		ParentBeaconBlockRoot: dec.ParentBeaconBlockRoot,
+		RequestsHash:          dec.RequestsHash,
+	}
+	if dec.Hash != nil {
+		h.Hash = *dec.Hash
+	}
+	if dec.Miner != nil {
+		h.Miner = *dec.Miner
+	}
+	if dec.LogsBloom != nil {
+		h.LogsBloom = *dec.LogsBloom
+	}
+	if dec.MixHash != nil {
+		h.MixHash = *dec.MixHash
+	}
+	if dec.Nonce != nil {
+		h.Nonce = *dec.Nonce
+	}
+}
+
+// Block is a block as returned by eth_getBlockByNumber and eth_getBlockByHash
+type Block struct {
+	Header
+	Size            uint64
+	TotalDifficulty *big.Int
+
+	// TransactionHashes holds the hashes of all transactions in the block
+	TransactionHashes []common.Hash
+	// Transactions holds the full transaction objects, it is only set when they were requested
+	Transactions []*Transaction
+	Uncles       []common.Hash
+	// Withdrawals is nil before Shanghai
+	Withdrawals []*Withdrawal
+}
+
+type blockJSON struct {
+	Size            hexutil.Uint64    `json:"size"`
+	TotalDifficulty *hexutil.Big      `json:"totalDifficulty,omitempty"`
+	Transactions    []json.RawMessage `json:"transactions"`
+	Uncles          []common.Hash     `json:"uncles"`
+	Withdrawals     []*Withdrawal     `json:"withdrawals,omitempty"`
+}
+
+// MarshalJSON encodes the block in the JSON-RPC format
+func (b Block) MarshalJSON() ([]byte, error) {
+	header, err := json.Marshal(b.Header.toJSON())
+	if err != nil {
+		return nil, err
+	}
+	enc := blockJSON{
+		Size:            hexutil.Uint64(b.Size),
+		TotalDifficulty: (*hexutil.Big)(b.TotalDifficulty),
+		Transactions:    make([]json.RawMessage, 0, len(b.TransactionHashes)),
+		Uncles:          b.Uncles,
+		Withdrawals:     b.Withdrawals,
+	}
+	if b.Transactions != nil {
+		for _, tx := range b.Transactions {
+			raw, err := json.Marshal(tx)
+			if err != nil {
+				return nil, err
+			}
+			enc.Transactions = append(enc.Transactions, raw)
+		}
+	} else {
+		for _, hash := range b.TransactionHashes {
+			raw, err := json.Marshal(hash)
+			if err != nil {
+				return nil, err
+			}
+			enc.Transactions = append(enc.Transactions, raw)
+		}
+	}
+	if enc.Uncles == nil {
+		enc.Uncles = []common.Hash{}
+	}
+	body, err := json.Marshal(&enc)
+	if err != nil {
+		return nil, err
+	}
+	return mergeJSONObjects(header, body), nil
+}
+
+// UnmarshalJSON decodes a JSON-RPC block with either transaction hashes or full transaction objects
+func (b *Block) UnmarshalJSON(input []byte) error {
+	var (
+		header headerJSON
+		body   blockJSON
+	)
+	if err := json.Unmarshal(input, &header); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(input, &body); err != nil {
+		return err
+	}
+	*b = Block{
+		Size:              uint64(body.Size),
+		TotalDifficulty:   (*big.Int)(body.TotalDifficulty),
+		TransactionHashes: make([]common.Hash, len(body.Transactions)),
+		Uncles:            body.Uncles,
+		Withdrawals:       body.Withdrawals,
+	}
+	b.Header.fromJSON(&header)
+
+	for i, raw := range body.Transactions {
+		if len(raw) > 0 && raw[0] == '"' {
+			if err := json.Unmarshal(raw, &b.TransactionHashes[i]); err != nil {
+				return err
+			}
+			continue
+		}
+		tx := new(Transaction)
+		if err := json.Unmarshal(raw, tx); err != nil {
+			return err
+		}
+		b.TransactionHashes[i] = tx.Hash
+		b.Transactions = append(b.Transactions, tx)
+	}
+	return nil
+}
+
+// mergeJSONObjects joins two encoded JSON objects into one
+func mergeJSONObjects(a, b []byte) []byte {
+	if len(b) <= 2 {
+		return a
+	}
+	if len(a) <= 2 {
+		return b
+	}
+	merged := make([]byte, 0, len(a)+len(b))
+	merged = append(merged, a[:len(a)-1]...)
+	merged = append(merged, ',')
+	return append(merged, b[1:]...)
+}
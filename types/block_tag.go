@@ -1,5 +1,10 @@
 package types
 
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+)
+
 type BlockTag string
 
 const (
@@ -18,3 +23,11 @@ const (
 	// PENDING status/Transaction
 	PENDING BlockTag = "pending"
 )
+
+// BlockNumber returns the block parameter for a block number, so it can be passed wherever a BlockTag is accepted
+func BlockNumber(number *big.Int) BlockTag {
+	if number == nil {
+		return LATEST
+	}
+	return BlockTag(hexutil.EncodeBig(number))
+}
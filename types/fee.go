@@ -0,0 +1,100 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+)
+
+// FeeHistoryResult is the fee history of a range of blocks as returned by eth_feeHistory
+type FeeHistoryResult struct {
+	OldestBlock *big.Int
+	// BaseFeePerGas holds the base fee of every block and of the block after the newest one
+	BaseFeePerGas []*big.Int
+	GasUsedRatio  []float64
+	// Reward holds the priority fees at the requested percentiles of every block
+	Reward [][]*big.Int
+	// BaseFeePerBlobGas and BlobGasUsedRatio are only set from Cancun on, like BaseFeePerGas
+	// the blob base fees include the block after the newest one
+	BaseFeePerBlobGas []*big.Int
+	BlobGasUsedRatio  []float64
+}
+
+type feeHistoryJSON struct {
+	OldestBlock       *hexutil.Big     `json:"oldestBlock"`
+	BaseFeePerGas     []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio      []float64        `json:"gasUsedRatio"`
+	Reward            [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFeePerBlobGas []*hexutil.Big   `json:"baseFeePerBlobGas,omitempty"`
+	BlobGasUsedRatio  []float64        `json:"blobGasUsedRatio,omitempty"`
+}
+
+// MarshalJSON encodes the fee history in the JSON-RPC format
+func (f FeeHistoryResult) MarshalJSON() ([]byte, error) {
+	enc := feeHistoryJSON{
+		OldestBlock:       (*hexutil.Big)(f.OldestBlock),
+		BaseFeePerGas:     toHexBigs(f.BaseFeePerGas),
+		GasUsedRatio:      f.GasUsedRatio,
+		BaseFeePerBlobGas: toHexBigs(f.BaseFeePerBlobGas),
+		BlobGasUsedRatio:  f.BlobGasUsedRatio,
+	}
+	if f.Reward != nil {
+		enc.Reward = make([][]*hexutil.Big, len(f.Reward))
+		for i, rewards := range f.Reward {
+			enc.Reward[i] = toHexBigs(rewards)
+		}
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a JSON-RPC fee history
+func (f *FeeHistoryResult) UnmarshalJSON(input []byte) error {
+	var dec feeHistoryJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*f = FeeHistoryResult{
+		OldestBlock:       (*big.Int)(dec.OldestBlock),
+		BaseFeePerGas:     fromHexBigs(dec.BaseFeePerGas),
+		GasUsedRatio:      dec.GasUsedRatio,
+		BaseFeePerBlobGas: fromHexBigs(dec.BaseFeePerBlobGas),
+		BlobGasUsedRatio:  dec.BlobGasUsedRatio,
+	}
+	if dec.Reward != nil {
+		f.Reward = make([][]*big.Int, len(dec.Reward))
+		for i, rewards := range dec.Reward {
+			f.Reward[i] = fromHexBigs(rewards)
+		}
+	}
+	return nil
+}
+
+// FeesPerGas are the fee values of a transaction, GasPrice is set for legacy transactions and
+// MaxFeePerGas and MaxPriorityFeePerGas for EIP-1559 transactions
+type FeesPerGas struct {
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+}
+
+func toHexBigs(values []*big.Int) []*hexutil.Big {
+	if values == nil {
+		return nil
+	}
+	enc := make([]*hexutil.Big, len(values))
+	for i, v := range values {
+		enc[i] = (*hexutil.Big)(v)
+	}
+	return enc
+}
+
+func fromHexBigs(values []*hexutil.Big) []*big.Int {
+	if values == nil {
+		return nil
+	}
+	dec := make([]*big.Int, len(values))
+	for i, v := range values {
+		dec[i] = (*big.Int)(v)
+	}
+	return dec
+}
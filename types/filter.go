@@ -0,0 +1,52 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LogFilter selects logs for eth_getLogs and logs subscriptions
+type LogFilter struct {
+	// Addresses matches logs emitted by any of the contracts, empty matches all contracts
+	Addresses []common.Address
+	// Topics matches topics by position, every position is an OR-set and an empty position matches any topic
+	Topics [][]common.Hash
+	// FromBlock and ToBlock accept tags or types.BlockNumber, empty means "latest"
+	FromBlock BlockTag
+	ToBlock   BlockTag
+	// BlockHash restricts the filter to a single block, FromBlock and ToBlock must be empty then
+	BlockHash *common.Hash
+}
+
+// MarshalJSON encodes the filter in the JSON-RPC format
+func (f LogFilter) MarshalJSON() ([]byte, error) {
+	enc := make(map[string]any)
+	if len(f.Addresses) > 0 {
+		enc["address"] = f.Addresses
+	}
+	if len(f.Topics) > 0 {
+		topics := make([]any, len(f.Topics))
+		for i, set := range f.Topics {
+			switch len(set) {
+			case 0:
+				topics[i] = nil
+			case 1:
+				topics[i] = set[0]
+			default:
+				topics[i] = set
+			}
+		}
+		enc["topics"] = topics
+	}
+	if f.BlockHash != nil {
+		enc["blockHash"] = f.BlockHash
+	} else {
+		if f.FromBlock != "" {
+			enc["fromBlock"] = f.FromBlock
+		}
+		if f.ToBlock != "" {
+			enc["toBlock"] = f.ToBlock
+		}
+	}
+	return json.Marshal(enc)
+}
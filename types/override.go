@@ -0,0 +1,89 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+)
+
+// StateOverride replaces parts of the state of accounts for the duration of a call
+type StateOverride map[common.Address]AccountOverride
+
+// AccountOverride is the state override of a single account, nil fields keep the current state
+type AccountOverride struct {
+	Balance *big.Int
+	Nonce   *uint64
+	// Code replaces the code of the account, e.g. to inject contracts that are not deployed
+	Code []byte
+	// State replaces the whole storage of the account, StateDiff only the given slots.
+	// They are mutually exclusive
+	State     map[common.Hash]common.Hash
+	StateDiff map[common.Hash]common.Hash
+	// MovePrecompileTo moves the precompile at this address so its code can be overridden
+	MovePrecompileTo *common.Address
+}
+
+type accountOverrideJSON struct {
+	Balance          *hexutil.Big                 `json:"balance,omitempty"`
+	Nonce            *hexutil.Uint64              `json:"nonce,omitempty"`
+	Code             *hexutil.Bytes               `json:"code,omitempty"`
+	State            *map[common.Hash]common.Hash `json:"state,omitempty"`
+	StateDiff        *map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+	MovePrecompileTo *common.Address              `json:"movePrecompileToAddress,omitempty"`
+}
+
+// MarshalJSON encodes the override in the format geth expects
+func (o AccountOverride) MarshalJSON() ([]byte, error) {
+	enc := accountOverrideJSON{
+		Balance:          (*hexutil.Big)(o.Balance),
+		Nonce:            (*hexutil.Uint64)(o.Nonce),
+		MovePrecompileTo: o.MovePrecompileTo,
+	}
+	if o.Code != nil {
+		enc.Code = (*hexutil.Bytes)(&o.Code)
+	}
+	// an empty state clears the whole storage, so only nil maps are omitted
+	if o.State != nil {
+		enc.State = &o.State
+	}
+	if o.StateDiff != nil {
+		enc.StateDiff = &o.StateDiff
+	}
+	return json.Marshal(&enc)
+}
+
+// BlockOverrides replaces fields of the block context a call is executed in, nil fields keep the block's values
+type BlockOverrides struct {
+	Number *big.Int
+	Time   *uint64
+	// FeeRecipient is the coinbase of the block
+	FeeRecipient  *common.Address
+	GasLimit      *uint64
+	PrevRandao    *common.Hash
+	BaseFeePerGas *big.Int
+	BlobBaseFee   *big.Int
+}
+
+type blockOverridesJSON struct {
+	Number        *hexutil.Big    `json:"number,omitempty"`
+	Time          *hexutil.Uint64 `json:"time,omitempty"`
+	FeeRecipient  *common.Address `json:"feeRecipient,omitempty"`
+	GasLimit      *hexutil.Uint64 `json:"gasLimit,omitempty"`
+	PrevRandao    *common.Hash    `json:"prevRandao,omitempty"`
+	BaseFeePerGas *hexutil.Big    `json:"baseFeePerGas,omitempty"`
+	BlobBaseFee   *hexutil.Big    `json:"blobBaseFee,omitempty"`
+}
+
+// MarshalJSON encodes the overrides in the format geth expects
+func (o BlockOverrides) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&blockOverridesJSON{
+		Number:        (*hexutil.Big)(o.Number),
+		Time:          (*hexutil.Uint64)(o.Time),
+		FeeRecipient:  o.FeeRecipient,
+		GasLimit:      (*hexutil.Uint64)(o.GasLimit),
+		PrevRandao:    o.PrevRandao,
+		BaseFeePerGas: (*hexutil.Big)(o.BaseFeePerGas),
+		BlobBaseFee:   (*hexutil.Big)(o.BlobBaseFee),
+	})
+}
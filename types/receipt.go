@@ -0,0 +1,181 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+const (
+	// ReceiptStatusFailed is the status of a reverted transaction
+	ReceiptStatusFailed = uint64(0)
+
+	// ReceiptStatusSuccessful is the status of a successful transaction
+	ReceiptStatusSuccessful = uint64(1)
+)
+
+// Receipt is a transaction receipt as returned by the JSON-RPC API
+type Receipt struct {
+	TransactionHash   common.Hash
+	TransactionIndex  uint64
+	BlockHash         common.Hash
+	BlockNumber       *big.Int
+	From              common.Address
+	To                *common.Address
+	Type              uint8
+	Status            uint64
+	CumulativeGasUsed uint64
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	ContractAddress   *common.Address
+	Logs              []*Log
+	LogsBloom         ethTypes.Bloom
+
+	// Root is the post-transaction state root of pre-Byzantium receipts
+	Root []byte
+
+	// BlobGasUsed and BlobGasPrice are only set for blob transactions
+	BlobGasUsed  *uint64
+	BlobGasPrice *big.Int
+}
+
+type receiptJSON struct {
+	TransactionHash   common.Hash     `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint64  `json:"transactionIndex"`
+	BlockHash         common.Hash     `json:"blockHash"`
+	BlockNumber       *hexutil.Big    `json:"blockNumber"`
+	From              common.Address  `json:"from"`
+	To                *common.Address `json:"to"`
+	Type              hexutil.Uint64  `json:"type"`
+	Status            *hexutil.Uint64 `json:"status,omitempty"`
+	CumulativeGasUsed hexutil.Uint64  `json:"cumulativeGasUsed"`
+	GasUsed           hexutil.Uint64  `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big    `json:"effectiveGasPrice"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	Logs              []*Log          `json:"logs"`
+	LogsBloom         ethTypes.Bloom  `json:"logsBloom"`
+	Root              hexutil.Bytes   `json:"root,omitempty"`
+	BlobGasUsed       *hexutil.Uint64 `json:"blobGasUsed,omitempty"`
+	BlobGasPrice      *hexutil.Big    `json:"blobGasPrice,omitempty"`
+}
+
+// MarshalJSON encodes the receipt in the JSON-RPC format
+func (r Receipt) MarshalJSON() ([]byte, error) {
+	enc := receiptJSON{
+		TransactionHash:   r.TransactionHash,
+		TransactionIndex:  hexutil.Uint64(r.TransactionIndex),
+		BlockHash:         r.BlockHash,
+		BlockNumber:       (*hexutil.Big)(r.BlockNumber),
+		From:              r.From,
+		To:                r.To,
+		Type:              hexutil.Uint64(r.Type),
+		CumulativeGasUsed: hexutil.Uint64(r.CumulativeGasUsed),
+		GasUsed:           hexutil.Uint64(r.GasUsed),
+		EffectiveGasPrice: (*hexutil.Big)(r.EffectiveGasPrice),
+		ContractAddress:   r.ContractAddress,
+		Logs:              r.Logs,
+		LogsBloom:         r.LogsBloom,
+		Root:              r.Root,
+		BlobGasUsed:       (*hexutil.Uint64)(r.BlobGasUsed),
+		BlobGasPrice:      (*hexutil.Big)(r.BlobGasPrice),
+	}
+	if len(r.Root) == 0 {
+		enc.Status = (*hexutil.Uint64)(&r.Status)
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a JSON-RPC receipt
+func (r *Receipt) UnmarshalJSON(input []byte) error {
+	var dec receiptJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*r = Receipt{
+		TransactionHash:   dec.TransactionHash,
+		TransactionIndex:  uint64(dec.TransactionIndex),
+		BlockHash:         dec.BlockHash,
+		BlockNumber:       (*big.Int)(dec.BlockNumber),
+		From:              dec.From,
+		To:                dec.To,
+		Type:              uint8(dec.Type),
+		CumulativeGasUsed: uint64(dec.CumulativeGasUsed),
+		GasUsed:           uint64(dec.GasUsed),
+		EffectiveGasPrice: (*big.Int)(dec.EffectiveGasPrice),
+		ContractAddress:   dec.ContractAddress,
+		Logs:              dec.Logs,
+		LogsBloom:         dec.LogsBloom,
+		Root:              dec.Root,
+		BlobGasUsed:       (*uint64)(dec.BlobGasUsed),
+		BlobGasPrice:      (*big.Int)(dec.BlobGasPrice),
+	}
+	if dec.Status != nil {
+		r.Status = uint64(*dec.Status)
+	}
+	return nil
+}
+
+// Log is a contract log event as returned by receipts, eth_getLogs and logs subscriptions.
+// The block and transaction fields are nil for pending logs
+type Log struct {
+	Address          common.Address
+	Topics           []common.Hash
+	Data             []byte
+	BlockNumber      *big.Int
+	BlockHash        *common.Hash
+	TransactionHash  *common.Hash
+	TransactionIndex *uint64
+	LogIndex         *uint64
+
+	// Removed is true when the log was reverted due to a chain reorganisation
+	Removed bool
+}
+
+type logJSON struct {
+	Address          common.Address  `json:"address"`
+	Topics           []common.Hash   `json:"topics"`
+	Data             hexutil.Bytes   `json:"data"`
+	BlockNumber      *hexutil.Big    `json:"blockNumber"`
+	BlockHash        *common.Hash    `json:"blockHash"`
+	TransactionHash  *common.Hash    `json:"transactionHash"`
+	TransactionIndex *hexutil.Uint64 `json:"transactionIndex"`
+	LogIndex         *hexutil.Uint64 `json:"logIndex"`
+	Removed          bool            `json:"removed"`
+}
+
+// MarshalJSON encodes the log in the JSON-RPC format
+func (l Log) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&logJSON{
+		Address:          l.Address,
+		Topics:           l.Topics,
+		Data:             l.Data,
+		BlockNumber:      (*hexutil.Big)(l.BlockNumber),
+		BlockHash:        l.BlockHash,
+		TransactionHash:  l.TransactionHash,
+		TransactionIndex: (*hexutil.Uint64)(l.TransactionIndex),
+		LogIndex:         (*hexutil.Uint64)(l.LogIndex),
+		Removed:          l.Removed,
+	})
+}
+
+// UnmarshalJSON decodes a JSON-RPC log
+func (l *Log) UnmarshalJSON(input []byte) error {
+	var dec logJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*l = Log{
+		Address:          dec.Address,
+		Topics:           dec.Topics,
+		Data:             dec.Data,
+		BlockNumber:      (*big.Int)(dec.BlockNumber),
+		BlockHash:        dec.BlockHash,
+		TransactionHash:  dec.TransactionHash,
+		TransactionIndex: (*uint64)(dec.TransactionIndex),
+		LogIndex:         (*uint64)(dec.LogIndex),
+		Removed:          dec.Removed,
+	}
+	return nil
+}
@@ -15,11 +15,7 @@ const (
 	GetUncleCountByBlockHash         RPCMethod = "eth_getUncleCountByBlockHash"
 	GetUncleCountByBlockNumber       RPCMethod = "eth_getUncleCountByBlockNumber"
 
-	WatchBlocks      RPCMethod = "eth_watchBlocks"
-	WatchBlockNumber RPCMethod = "eth_watchBlockNumber"
-
-	SimulateCall   RPCMethod = "eth_simulateCall"
-	SimulateBlocks RPCMethod = "eth_simulateBlocks"
+	SimulateV1 RPCMethod = "eth_simulateV1"
 )
 
 // Transaction  API
@@ -48,10 +44,12 @@ const (
 
 // chain API / other API
 const (
-	CreateAccessList RPCMethod = "eth_createAccessList"
-	GasPrice         RPCMethod = "eth_gasPrice"
-	Syncing          RPCMethod = "eth_syncing"
-	ProtocolVersion  RPCMethod = "eth_protocolVersion"
+	CreateAccessList     RPCMethod = "eth_createAccessList"
+	GasPrice             RPCMethod = "eth_gasPrice"
+	MaxPriorityFeePerGas RPCMethod = "eth_maxPriorityFeePerGas"
+	BlobBaseFee          RPCMethod = "eth_blobBaseFee"
+	Syncing              RPCMethod = "eth_syncing"
+	ProtocolVersion      RPCMethod = "eth_protocolVersion"
 )
 
 // net api
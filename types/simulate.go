@@ -0,0 +1,154 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SimulateParams are the inputs of eth_simulateV1
+type SimulateParams struct {
+	// Blocks are simulated in order, each on top of the previous one
+	Blocks []SimulateBlock
+	// Validation enables the checks of real transactions like nonces, balances and base fees
+	Validation bool
+	// TraceTransfers adds an ERC-20 like Transfer log from the zero address contract for every ETH transfer
+	TraceTransfers bool
+	// ReturnFullTransactions returns full transaction objects instead of hashes in the simulated blocks
+	ReturnFullTransactions bool
+}
+
+// SimulateBlock is a simulated block with the overrides applied before its calls are executed in order
+type SimulateBlock struct {
+	BlockOverrides *BlockOverrides
+	StateOverrides StateOverride
+	// Calls are transaction objects like the call of eth_call
+	Calls []map[string]any
+}
+
+type simulateBlockJSON struct {
+	BlockOverrides *BlockOverrides  `json:"blockOverrides,omitempty"`
+	StateOverrides StateOverride    `json:"stateOverrides,omitempty"`
+	Calls          []map[string]any `json:"calls"`
+}
+
+type simulateParamsJSON struct {
+	BlockStateCalls        []simulateBlockJSON `json:"blockStateCalls"`
+	Validation             bool                `json:"validation"`
+	TraceTransfers         bool                `json:"traceTransfers"`
+	ReturnFullTransactions bool                `json:"returnFullTransactions"`
+}
+
+// MarshalJSON encodes the params in the JSON-RPC format
+func (p SimulateParams) MarshalJSON() ([]byte, error) {
+	enc := simulateParamsJSON{
+		BlockStateCalls:        make([]simulateBlockJSON, len(p.Blocks)),
+		Validation:             p.Validation,
+		TraceTransfers:         p.TraceTransfers,
+		ReturnFullTransactions: p.ReturnFullTransactions,
+	}
+	for i, block := range p.Blocks {
+		enc.BlockStateCalls[i] = simulateBlockJSON(block)
+		if block.Calls == nil {
+			enc.BlockStateCalls[i].Calls = []map[string]any{}
+		}
+	}
+	return json.Marshal(&enc)
+}
+
+// SimulatedBlock is a block returned by eth_simulateV1 with the results of its calls
+type SimulatedBlock struct {
+	Block
+	Calls []SimulateCallResult
+}
+
+type simulatedBlockJSON struct {
+	Calls []SimulateCallResult `json:"calls"`
+}
+
+// MarshalJSON encodes the block in the JSON-RPC format
+func (b SimulatedBlock) MarshalJSON() ([]byte, error) {
+	block, err := json.Marshal(b.Block)
+	if err != nil {
+		return nil, err
+	}
+	calls, err := json.Marshal(&simulatedBlockJSON{Calls: b.Calls})
+	if err != nil {
+		return nil, err
+	}
+	return mergeJSONObjects(block, calls), nil
+}
+
+// UnmarshalJSON decodes a JSON-RPC simulated block
+func (b *SimulatedBlock) UnmarshalJSON(input []byte) error {
+	var dec simulatedBlockJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(input, &b.Block); err != nil {
+		return err
+	}
+	b.Calls = dec.Calls
+	return nil
+}
+
+// SimulateCallResult is the outcome of a simulated call
+type SimulateCallResult struct {
+	ReturnData []byte
+	Logs       []*Log
+	GasUsed    uint64
+	// Status is ReceiptStatusSuccessful or ReceiptStatusFailed
+	Status uint64
+	// Error is set for failed calls
+	Error *SimulateCallError
+}
+
+type simulateCallResultJSON struct {
+	ReturnData hexutil.Bytes      `json:"returnData"`
+	Logs       []*Log             `json:"logs"`
+	GasUsed    hexutil.Uint64     `json:"gasUsed"`
+	Status     hexutil.Uint64     `json:"status"`
+	Error      *SimulateCallError `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes the result in the JSON-RPC format
+func (r SimulateCallResult) MarshalJSON() ([]byte, error) {
+	enc := simulateCallResultJSON{
+		ReturnData: r.ReturnData,
+		Logs:       r.Logs,
+		GasUsed:    hexutil.Uint64(r.GasUsed),
+		Status:     hexutil.Uint64(r.Status),
+		Error:      r.Error,
+	}
+	if enc.Logs == nil {
+		enc.Logs = []*Log{}
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a JSON-RPC simulated call result
+func (r *SimulateCallResult) UnmarshalJSON(input []byte) error {
+	var dec simulateCallResultJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*r = SimulateCallResult{
+		ReturnData: dec.ReturnData,
+		Logs:       dec.Logs,
+		GasUsed:    uint64(dec.GasUsed),
+		Status:     uint64(dec.Status),
+		Error:      dec.Error,
+	}
+	return nil
+}
+
+// SimulateCallError is the error of a failed simulated call, reverts carry their revert data in Data
+type SimulateCallError struct {
+	Code    int           `json:"code"`
+	Message string        `json:"message"`
+	Data    hexutil.Bytes `json:"data,omitempty"`
+}
+
+func (e *SimulateCallError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
@@ -0,0 +1,14 @@
+package types
+
+type SubscriptionKind string
+
+const (
+	// NewHeadsSubscription new block headers
+	NewHeadsSubscription SubscriptionKind = "newHeads"
+
+	// LogsSubscription logs matching a filter
+	LogsSubscription SubscriptionKind = "logs"
+
+	// NewPendingTransactionsSubscription hashes of transactions entering the pending pool
+	NewPendingTransactionsSubscription SubscriptionKind = "newPendingTransactions"
+)
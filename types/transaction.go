@@ -0,0 +1,175 @@
+package types
+
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// Transaction is a transaction as returned by the JSON-RPC API.
+// BlockHash, BlockNumber and TransactionIndex are nil for pending transactions
+type Transaction struct {
+	Hash             common.Hash
+	BlockHash        *common.Hash
+	BlockNumber      *big.Int
+	TransactionIndex *uint64
+	Type             uint8
+	ChainID          *big.Int
+	From             common.Address
+	To               *common.Address
+	Nonce            uint64
+	Gas              uint64
+	Value            *big.Int
+	Input            []byte
+
+	// GasPrice is the effective gas price for mined EIP-1559 transactions
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	MaxFeePerBlobGas     *big.Int
+
+	AccessList          ethTypes.AccessList
+	BlobVersionedHashes []common.Hash
+	AuthorizationList   []ethTypes.SetCodeAuthorization
+
+	V       *big.Int
+	R       *big.Int
+	S       *big.Int
+	YParity *uint64
+}
+
+type transactionJSON struct {
+	Hash                 common.Hash                     `json:"hash"`
+	BlockHash            *common.Hash                    `json:"blockHash"`
+	BlockNumber          *hexutil.Big                    `json:"blockNumber"`
+	TransactionIndex     *hexutil.Uint64                 `json:"transactionIndex"`
+	Type                 hexutil.Uint64                  `json:"type"`
+	ChainID              *hexutil.Big                    `json:"chainId,omitempty"`
+	From                 common.Address                  `json:"from"`
+	To                   *common.Address                 `json:"to"`
+	Nonce                hexutil.Uint64                  `json:"nonce"`
+	Gas                  hexutil.Uint64                  `json:"gas"`
+	Value                *hexutil.Big                    `json:"value"`
+	Input                hexutil.Bytes                   `json:"input"`
+	GasPrice             *hexutil.Big                    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big                    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big                    `json:"maxPriorityFeePerGas,omitempty"`
+	MaxFeePerBlobGas     *hexutil.Big                    `json:"maxFeePerBlobGas,omitempty"`
+	AccessList           *ethTypes.AccessList            `json:"accessList,omitempty"`
+	BlobVersionedHashes  []common.Hash                   `json:"blobVersionedHashes,omitempty"`
+	AuthorizationList    []ethTypes.SetCodeAuthorization `json:"authorizationList,omitempty"`
+	V                    *hexutil.Big                    `json:"v,omitempty"`
+	R                    *hexutil.Big                    `json:"r,omitempty"`
+	S                    *hexutil.Big                    `json:"s,omitempty"`
+	YParity              *hexutil.Uint64                 `json:"yParity,omitempty"`
+}
+
+// MarshalJSON encodes the transaction in the JSON-RPC format
+func (tx Transaction) MarshalJSON() ([]byte, error) {
+	enc := transactionJSON{
+		Hash:                 tx.Hash,
+		BlockHash:            tx.BlockHash,
+		BlockNumber:          (*hexutil.Big)(tx.BlockNumber),
+		TransactionIndex:     (*hexutil.Uint64)(tx.TransactionIndex),
+		Type:                 hexutil.Uint64(tx.Type),
+		ChainID:              (*hexutil.Big)(tx.ChainID),
+		From:                 tx.From,
+		To:                   tx.To,
+		Nonce:                hexutil.Uint64(tx.Nonce),
+		Gas:                  hexutil.Uint64(tx.Gas),
+		Value:                (*hexutil.Big)(tx.Value),
+		Input:                tx.Input,
+		GasPrice:             (*hexutil.Big)(tx.GasPrice),
+		MaxFeePerGas:         (*hexutil.Big)(tx.MaxFeePerGas),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.MaxPriorityFeePerGas),
+		MaxFeePerBlobGas:     (*hexutil.Big)(tx.MaxFeePerBlobGas),
+		BlobVersionedHashes:  tx.BlobVersionedHashes,
+		AuthorizationList:    tx.AuthorizationList,
+		V:                    (*hexutil.Big)(tx.V),
+		R:                    (*hexutil.Big)(tx.R),
+		S:                    (*hexutil.Big)(tx.S),
+		YParity:              (*hexutil.Uint64)(tx.YParity),
+	}
+	if tx.AccessList != nil {
+		enc.AccessList = &tx.AccessList
+	}
+	return json.Marshal(&enc)
+}
+
+// UnmarshalJSON decodes a JSON-RPC transaction
+func (tx *Transaction) UnmarshalJSON(input []byte) error {
+	var dec transactionJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*tx = Transaction{
+		Hash:                 dec.Hash,
+		BlockHash:            dec.BlockHash,
+		BlockNumber:          (*big.Int)(dec.BlockNumber),
+		TransactionIndex:     (*uint64)(dec.TransactionIndex),
+		Type:                 uint8(dec.Type),
+		ChainID:              (*big.Int)(dec.ChainID),
+		From:                 dec.From,
+		To:                   dec.To,
+		Nonce:                uint64(dec.Nonce),
+		Gas:                  uint64(dec.Gas),
+		Value:                (*big.Int)(dec.Value),
+		Input:                dec.Input,
+		GasPrice:             (*big.Int)(dec.GasPrice),
+		MaxFeePerGas:         (*big.Int)(dec.MaxFeePerGas),
+		MaxPriorityFeePerGas: (*big.Int)(dec.MaxPriorityFeePerGas),
+		MaxFeePerBlobGas:     (*big.Int)(dec.MaxFeePerBlobGas),
+		BlobVersionedHashes:  dec.BlobVersionedHashes,
+		AuthorizationList:    dec.AuthorizationList,
+		V:                    (*big.Int)(dec.V),
+		R:                    (*big.Int)(dec.R),
+		S:                    (*big.Int)(dec.S),
+		YParity:              (*uint64)(dec.YParity),
+	}
+	if dec.AccessList != nil {
+		tx.AccessList = *dec.AccessList
+	}
+	return nil
+}
+
+// Withdrawal is a validator withdrawal included in a block, Amount is denominated in gwei
+type Withdrawal struct {
+	Index          uint64
+	ValidatorIndex uint64
+	Address        common.Address
+	Amount         uint64
+}
+
+type withdrawalJSON struct {
+	Index          hexutil.Uint64 `json:"index"`
+	ValidatorIndex hexutil.Uint64 `json:"validatorIndex"`
+	Address        common.Address `json:"address"`
+	Amount         hexutil.Uint64 `json:"amount"`
+}
+
+// MarshalJSON encodes the withdrawal in the JSON-RPC format
+func (w Withdrawal) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&withdrawalJSON{
+		Index:          hexutil.Uint64(w.Index),
+		ValidatorIndex: hexutil.Uint64(w.ValidatorIndex),
+		Address:        w.Address,
+		Amount:         hexutil.Uint64(w.Amount),
+	})
+}
+
+// UnmarshalJSON decodes a JSON-RPC withdrawal
+func (w *Withdrawal) UnmarshalJSON(input []byte) error {
+	var dec withdrawalJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*w = Withdrawal{
+		Index:          uint64(dec.Index),
+		ValidatorIndex: uint64(dec.ValidatorIndex),
+		Address:        dec.Address,
+		Amount:         uint64(dec.Amount),
+	}
+	return nil
+}
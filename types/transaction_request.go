@@ -0,0 +1,220 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"math/big"
+)
+
+// TransactionRequest is a transaction to be sent. Unset fields are filled by PrepareTransactionRequest,
+// the transaction type is inferred from the fields that are set unless Type is given
+type TransactionRequest struct {
+	// From is the sender, the zero address stands for the account of the client
+	From common.Address
+	// To is nil for contract deployments
+	To    *common.Address
+	Value *big.Int
+	Data  []byte
+	Nonce *uint64
+	// Gas is the gas limit, zero is estimated
+	Gas     uint64
+	ChainID *big.Int
+	Type    *uint8
+
+	// GasPrice is set for legacy and EIP-2930 transactions
+	GasPrice *big.Int
+	// MaxFeePerGas and MaxPriorityFeePerGas are set for EIP-1559, EIP-4844 and EIP-7702 transactions
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+
+	AccessList ethTypes.AccessList
+
+	// MaxFeePerBlobGas, BlobVersionedHashes and Sidecar are set for EIP-4844 transactions.
+	// BlobVersionedHashes are derived from the Sidecar when only the Sidecar is set
+	MaxFeePerBlobGas    *big.Int
+	BlobVersionedHashes []common.Hash
+	Sidecar             *ethTypes.BlobTxSidecar
+
+	// AuthorizationList is set for EIP-7702 transactions
+	AuthorizationList []ethTypes.SetCodeAuthorization
+}
+
+type transactionRequestJSON struct {
+	From                 *common.Address                 `json:"from,omitempty"`
+	To                   *common.Address                 `json:"to,omitempty"`
+	Value                *hexutil.Big                    `json:"value,omitempty"`
+	Data                 hexutil.Bytes                   `json:"data,omitempty"`
+	Nonce                *hexutil.Uint64                 `json:"nonce,omitempty"`
+	Gas                  *hexutil.Uint64                 `json:"gas,omitempty"`
+	ChainID              *hexutil.Big                    `json:"chainId,omitempty"`
+	Type                 *hexutil.Uint64                 `json:"type,omitempty"`
+	GasPrice             *hexutil.Big                    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big                    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big                    `json:"maxPriorityFeePerGas,omitempty"`
+	AccessList           *ethTypes.AccessList            `json:"accessList,omitempty"`
+	MaxFeePerBlobGas     *hexutil.Big                    `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes  []common.Hash                   `json:"blobVersionedHashes,omitempty"`
+	AuthorizationList    []ethTypes.SetCodeAuthorization `json:"authorizationList,omitempty"`
+}
+
+// MarshalJSON encodes the request as a JSON-RPC transaction object like eth_call and eth_estimateGas take
+func (r TransactionRequest) MarshalJSON() ([]byte, error) {
+	enc := transactionRequestJSON{
+		To:                   r.To,
+		Value:                (*hexutil.Big)(r.Value),
+		Data:                 r.Data,
+		Nonce:                (*hexutil.Uint64)(r.Nonce),
+		ChainID:              (*hexutil.Big)(r.ChainID),
+		GasPrice:             (*hexutil.Big)(r.GasPrice),
+		MaxFeePerGas:         (*hexutil.Big)(r.MaxFeePerGas),
+		MaxPriorityFeePerGas: (*hexutil.Big)(r.MaxPriorityFeePerGas),
+		MaxFeePerBlobGas:     (*hexutil.Big)(r.MaxFeePerBlobGas),
+		BlobVersionedHashes:  r.blobHashes(),
+		AuthorizationList:    r.AuthorizationList,
+	}
+	if r.From != (common.Address{}) {
+		enc.From = &r.From
+	}
+	if r.Gas != 0 {
+		enc.Gas = (*hexutil.Uint64)(&r.Gas)
+	}
+	if r.Type != nil {
+		txType := hexutil.Uint64(*r.Type)
+		enc.Type = &txType
+	}
+	if r.AccessList != nil {
+		enc.AccessList = &r.AccessList
+	}
+	return json.Marshal(&enc)
+}
+
+// InferType returns Type or infers the transaction type from the fields that are set: an authorization list
+// makes an EIP-7702 transaction, blob fields an EIP-4844 transaction, EIP-1559 fees an EIP-1559 transaction
+// and a gas price a legacy transaction, or an EIP-2930 transaction with an access list.
+// It reports false when no field decides the type
+func (r *TransactionRequest) InferType() (uint8, bool) {
+	switch {
+	case r.Type != nil:
+		return *r.Type, true
+	case len(r.AuthorizationList) > 0:
+		return ethTypes.SetCodeTxType, true
+	case len(r.BlobVersionedHashes) > 0 || r.Sidecar != nil || r.MaxFeePerBlobGas != nil:
+		return ethTypes.BlobTxType, true
+	case r.MaxFeePerGas != nil || r.MaxPriorityFeePerGas != nil:
+		return ethTypes.DynamicFeeTxType, true
+	case r.GasPrice != nil && r.AccessList != nil:
+		return ethTypes.AccessListTxType, true
+	case r.GasPrice != nil:
+		return ethTypes.LegacyTxType, true
+	}
+	return 0, false
+}
+
+// TxData converts a prepared request into the transaction to sign. The chain ID, nonce, gas and the fees
+// of the transaction type have to be set
+func (r *TransactionRequest) TxData() (ethTypes.TxData, error) {
+	txType, ok := r.InferType()
+	if !ok {
+		return nil, errors.New("transaction type is unknown, set the fees or the type")
+	}
+	if r.ChainID == nil || r.Nonce == nil || r.Gas == 0 {
+		return nil, errors.New("transaction is not prepared, chain id, nonce and gas are required")
+	}
+	value := r.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	switch txType {
+	case ethTypes.LegacyTxType, ethTypes.AccessListTxType:
+		if r.GasPrice == nil {
+			return nil, errors.New("gas price is required")
+		}
+		if txType == ethTypes.LegacyTxType {
+			return &ethTypes.LegacyTx{Nonce: *r.Nonce, GasPrice: r.GasPrice, Gas: r.Gas, To: r.To, Value: value, Data: r.Data}, nil
+		}
+		return &ethTypes.AccessListTx{
+			ChainID: r.ChainID, Nonce: *r.Nonce, GasPrice: r.GasPrice, Gas: r.Gas, To: r.To, Value: value, Data: r.Data,
+			AccessList: r.AccessList,
+		}, nil
+	case ethTypes.DynamicFeeTxType, ethTypes.BlobTxType, ethTypes.SetCodeTxType:
+		if r.MaxFeePerGas == nil || r.MaxPriorityFeePerGas == nil {
+			return nil, errors.New("max fee per gas and max priority fee per gas are required")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", txType)
+	}
+
+	if txType == ethTypes.DynamicFeeTxType {
+		return &ethTypes.DynamicFeeTx{
+			ChainID: r.ChainID, Nonce: *r.Nonce, GasTipCap: r.MaxPriorityFeePerGas, GasFeeCap: r.MaxFeePerGas, Gas: r.Gas,
+			To: r.To, Value: value, Data: r.Data, AccessList: r.AccessList,
+		}, nil
+	}
+	if r.To == nil {
+		return nil, fmt.Errorf("transaction type %d cannot deploy contracts", txType)
+	}
+	chainID, err := toUint256(r.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("chain id: %w", err)
+	}
+	tip, err := toUint256(r.MaxPriorityFeePerGas)
+	if err != nil {
+		return nil, fmt.Errorf("max priority fee per gas: %w", err)
+	}
+	feeCap, err := toUint256(r.MaxFeePerGas)
+	if err != nil {
+		return nil, fmt.Errorf("max fee per gas: %w", err)
+	}
+	amount, err := toUint256(value)
+	if err != nil {
+		return nil, fmt.Errorf("value: %w", err)
+	}
+
+	if txType == ethTypes.SetCodeTxType {
+		return &ethTypes.SetCodeTx{
+			ChainID: chainID, Nonce: *r.Nonce, GasTipCap: tip, GasFeeCap: feeCap, Gas: r.Gas, To: *r.To,
+			Value: amount, Data: r.Data, AccessList: r.AccessList, AuthList: r.AuthorizationList,
+		}, nil
+	}
+	hashes := r.blobHashes()
+	if len(hashes) == 0 {
+		return nil, errors.New("blob versioned hashes are required")
+	}
+	if r.MaxFeePerBlobGas == nil {
+		return nil, errors.New("max fee per blob gas is required")
+	}
+	blobFeeCap, err := toUint256(r.MaxFeePerBlobGas)
+	if err != nil {
+		return nil, fmt.Errorf("max fee per blob gas: %w", err)
+	}
+	return &ethTypes.BlobTx{
+		ChainID: chainID, Nonce: *r.Nonce, GasTipCap: tip, GasFeeCap: feeCap, Gas: r.Gas, To: *r.To,
+		Value: amount, Data: r.Data, AccessList: r.AccessList, BlobFeeCap: blobFeeCap, BlobHashes: hashes,
+		Sidecar: r.Sidecar,
+	}, nil
+}
+
+// blobHashes returns BlobVersionedHashes or the hashes of the Sidecar blobs
+func (r *TransactionRequest) blobHashes() []common.Hash {
+	if r.BlobVersionedHashes == nil && r.Sidecar != nil {
+		return r.Sidecar.BlobHashes()
+	}
+	return r.BlobVersionedHashes
+}
+
+func toUint256(v *big.Int) (*uint256.Int, error) {
+	if v.Sign() < 0 {
+		return nil, errors.New("negative value")
+	}
+	u, overflow := uint256.FromBig(v)
+	if overflow {
+		return nil, errors.New("value exceeds 256 bits")
+	}
+	return u, nil
+}
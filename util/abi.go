@@ -1,15 +1,350 @@
 package util
 
 import (
+	"container/list"
+	"encoding/json"
+	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"maps"
+	"regexp"
 	"strings"
+	"sync"
 )
 
-// BuildCalldata generates ABI-encoded calldata for a method and args
+// abiCacheSize is the number of parsed ABIs kept by abiCache
+const abiCacheSize = 256
+
+// abiCache holds recently parsed ABIs by their definition, contract actions parse the same definition on every call
+var abiCache = newABILRU(abiCacheSize)
+
+// abiLRU is a least recently used cache of parsed ABIs
+type abiLRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type abiLRUEntry struct {
+	definition string
+	parsed     *abi.ABI
+}
+
+func newABILRU(size int) *abiLRU {
+	return &abiLRU{size: size, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *abiLRU) get(definition string) (*abi.ABI, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[definition]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*abiLRUEntry).parsed, true
+}
+
+func (c *abiLRU) add(definition string, parsed *abi.ABI) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[definition]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[definition] = c.order.PushFront(&abiLRUEntry{definition: definition, parsed: parsed})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*abiLRUEntry).definition)
+	}
+}
+
+// copyABI copies the method, event and error maps of an ABI, so callers can add or remove entries without
+// changing the cached ABI. The entries themselves are shared and must not be modified
+func copyABI(parsed *abi.ABI) *abi.ABI {
+	copied := *parsed
+	copied.Methods = maps.Clone(parsed.Methods)
+	copied.Events = maps.Clone(parsed.Events)
+	copied.Errors = maps.Clone(parsed.Errors)
+	return &copied
+}
+
+// BuildCalldata generates ABI-encoded calldata for a method and args, the ABI may be JSON or human-readable
 func BuildCalldata(abiJSON, method string, args ...interface{}) ([]byte, error) {
-	parsedABI, err := abi.JSON(strings.NewReader(abiJSON))
+	parsedABI, err := ParseABI(abiJSON)
 	if err != nil {
 		return nil, err
 	}
 	return parsedABI.Pack(method, args...)
 }
+
+// ParseABI parses a JSON ABI or a human-readable ABI. A human-readable ABI is either one signature per
+// line or a JSON array of signatures, e.g.
+//
+//	event Transfer(address indexed from, address indexed to, uint256 value)
+//	function balanceOf(address owner) view returns (uint256)
+//
+// The most recently parsed ABIs are cached. Every caller gets its own copy of the method, event and error maps,
+// the arguments of the entries are shared and must not be modified
+func ParseABI(definition string) (*abi.ABI, error) {
+	if cached, ok := abiCache.get(definition); ok {
+		return copyABI(cached), nil
+	}
+
+	var (
+		parsed *abi.ABI
+		err    error
+	)
+	trimmed := strings.TrimSpace(definition)
+	if strings.HasPrefix(trimmed, "[") {
+		var signatures []string
+		if json.Unmarshal([]byte(trimmed), &signatures) == nil {
+			parsed, err = ParseHumanReadableABI(signatures...)
+		} else {
+			var contractABI abi.ABI
+			contractABI, err = abi.JSON(strings.NewReader(trimmed))
+			parsed = &contractABI
+		}
+	} else {
+		parsed, err = ParseHumanReadableABI(strings.Split(trimmed, "\n")...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	abiCache.add(definition, parsed)
+	return copyABI(parsed), nil
+}
+
+// ParseHumanReadableABI parses function, event, error, constructor, fallback and receive signatures in the
+// Solidity format used by viem and ethers. Tuples are written as parenthesised component lists, empty
+// signatures are ignored
+func ParseHumanReadableABI(signatures ...string) (*abi.ABI, error) {
+	entries := make([]abiEntry, 0, len(signatures))
+	for _, signature := range signatures {
+		signature = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(signature), ";"))
+		if signature == "" {
+			continue
+		}
+		entry, err := parseSignature(signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature %q: %w", signature, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	encoded, err := json.Marshal(entries)
+	if err != nil {
+		return nil, err
+	}
+	contractABI, err := abi.JSON(strings.NewReader(string(encoded)))
+	if err != nil {
+		return nil, err
+	}
+	return &contractABI, nil
+}
+
+// abiEntry is a JSON ABI entry
+type abiEntry struct {
+	Type            string     `json:"type"`
+	Name            string     `json:"name,omitempty"`
+	Inputs          []abiParam `json:"inputs"`
+	Outputs         []abiParam `json:"outputs,omitempty"`
+	StateMutability string     `json:"stateMutability,omitempty"`
+	Anonymous       bool       `json:"anonymous,omitempty"`
+}
+
+// abiParam is a JSON ABI parameter
+type abiParam struct {
+	Name       string     `json:"name"`
+	Type       string     `json:"type"`
+	Indexed    bool       `json:"indexed,omitempty"`
+	Components []abiParam `json:"components,omitempty"`
+}
+
+// integerAlias matches the uint and int aliases for their 256 bit types
+var integerAlias = regexp.MustCompile(`^(u?int)(\[.*)?$`)
+
+// parseSignature turns a single human-readable signature into a JSON ABI entry
+func parseSignature(signature string) (abiEntry, error) {
+	open := strings.IndexByte(signature, '(')
+	if open < 0 {
+		return abiEntry{}, fmt.Errorf("missing parameter list")
+	}
+	end, err := closingParen(signature, open)
+	if err != nil {
+		return abiEntry{}, err
+	}
+	head := strings.Fields(signature[:open])
+	if len(head) == 0 {
+		return abiEntry{}, fmt.Errorf("missing signature type")
+	}
+
+	entry := abiEntry{Type: head[0]}
+	switch entry.Type {
+	case "function", "event", "error":
+		if len(head) != 2 {
+			return abiEntry{}, fmt.Errorf("missing %s name", entry.Type)
+		}
+		entry.Name = head[1]
+	case "constructor", "fallback", "receive":
+		if len(head) != 1 {
+			return abiEntry{}, fmt.Errorf("unexpected name for %s", entry.Type)
+		}
+	default:
+		return abiEntry{}, fmt.Errorf("unknown signature type %q", entry.Type)
+	}
+
+	if entry.Inputs, err = parseParams(signature[open+1:end], entry.Type == "event"); err != nil {
+		return abiEntry{}, err
+	}
+
+	var modifiers []string
+	tail := strings.TrimSpace(signature[end+1:])
+	for tail != "" {
+		if strings.HasPrefix(tail, "returns") {
+			tail = strings.TrimSpace(strings.TrimPrefix(tail, "returns"))
+			if !strings.HasPrefix(tail, "(") {
+				return abiEntry{}, fmt.Errorf("missing return parameter list")
+			}
+			end, err := closingParen(tail, 0)
+			if err != nil {
+				return abiEntry{}, err
+			}
+			if entry.Outputs, err = parseParams(tail[1:end], false); err != nil {
+				return abiEntry{}, err
+			}
+			tail = strings.TrimSpace(tail[end+1:])
+			continue
+		}
+		word, rest, _ := strings.Cut(tail, " ")
+		modifiers = append(modifiers, word)
+		tail = strings.TrimSpace(rest)
+	}
+
+	for _, modifier := range modifiers {
+		switch modifier {
+		case "view", "pure", "payable", "nonpayable":
+			entry.StateMutability = modifier
+		case "anonymous":
+			entry.Anonymous = true
+		case "external", "public", "virtual", "override":
+		default:
+			return abiEntry{}, fmt.Errorf("unknown modifier %q", modifier)
+		}
+	}
+	switch entry.Type {
+	case "function", "constructor", "fallback":
+		if entry.StateMutability == "" {
+			entry.StateMutability = "nonpayable"
+		}
+	case "receive":
+		entry.StateMutability = "payable"
+	}
+	if entry.Inputs == nil {
+		entry.Inputs = []abiParam{}
+	}
+	return entry, nil
+}
+
+// parseParams parses a comma separated parameter list without its parentheses
+func parseParams(list string, allowIndexed bool) ([]abiParam, error) {
+	var (
+		params []abiParam
+		depth  int
+		start  int
+	)
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	for i := 0; i <= len(list); i++ {
+		if i < len(list) {
+			switch list[i] {
+			case '(':
+				depth++
+				continue
+			case ')':
+				depth--
+				continue
+			case ',':
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		param, err := parseParam(strings.TrimSpace(list[start:i]), allowIndexed)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+		start = i + 1
+	}
+	return params, nil
+}
+
+// parseParam parses a single parameter like "address indexed from" or "(uint256 a, bytes b)[] pairs"
+func parseParam(param string, allowIndexed bool) (abiParam, error) {
+	var (
+		result abiParam
+		rest   string
+	)
+	if strings.HasPrefix(param, "tuple(") {
+		param = strings.TrimPrefix(param, "tuple")
+	}
+	if strings.HasPrefix(param, "(") {
+		end, err := closingParen(param, 0)
+		if err != nil {
+			return abiParam{}, err
+		}
+		if result.Components, err = parseParams(param[1:end], false); err != nil {
+			return abiParam{}, err
+		}
+		rest = param[end+1:]
+		suffix := rest[:len(rest)-len(strings.TrimLeft(rest, "[]0123456789"))]
+		result.Type = "tuple" + suffix
+		rest = rest[len(suffix):]
+	} else {
+		typ, after, _ := strings.Cut(param, " ")
+		if typ == "" {
+			return abiParam{}, fmt.Errorf("missing parameter type")
+		}
+		result.Type = integerAlias.ReplaceAllString(typ, "${1}256${2}")
+		rest = after
+	}
+
+	for _, word := range strings.Fields(rest) {
+		switch word {
+		case "indexed":
+			if !allowIndexed {
+				return abiParam{}, fmt.Errorf("indexed is only allowed for event parameters")
+			}
+			result.Indexed = true
+		case "memory", "calldata", "storage", "payable":
+		default:
+			if result.Name != "" {
+				return abiParam{}, fmt.Errorf("unexpected %q after parameter %s", word, result.Name)
+			}
+			result.Name = word
+		}
+	}
+	return result, nil
+}
+
+// closingParen returns the index of the parenthesis closing the one at open
+func closingParen(s string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses")
+}
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseABI_Cache(t *testing.T) {
+	definition := `function transfer(address to, uint256 amount) returns (bool)
+event Transfer(address indexed from, address indexed to, uint256 value)`
+	first, err := ParseABI(definition)
+	if err != nil {
+		t.Fatalf("ParseABI error: %v", err)
+	}
+	delete(first.Methods, "transfer")
+	delete(first.Events, "Transfer")
+
+	second, err := ParseABI(definition)
+	if err != nil {
+		t.Fatalf("ParseABI error: %v", err)
+	}
+	if _, ok := second.Methods["transfer"]; !ok {
+		t.Error("modifying a parsed ABI must not change the cached one")
+	}
+	if _, ok := second.Events["Transfer"]; !ok {
+		t.Error("modifying a parsed ABI must not change the cached one")
+	}
+}
+
+func TestABILRU(t *testing.T) {
+	cache := newABILRU(2)
+	for i := 0; i < 3; i++ {
+		definition := fmt.Sprintf("function f%d()", i)
+		parsed, err := ParseHumanReadableABI(definition)
+		if err != nil {
+			t.Fatalf("ParseHumanReadableABI error: %v", err)
+		}
+		cache.add(definition, parsed)
+		if i == 1 {
+			// f0 becomes the most recently used, f1 is evicted next
+			cache.get("function f0()")
+		}
+	}
+	if _, ok := cache.get("function f1()"); ok {
+		t.Error("expected the least recently used ABI to be evicted")
+	}
+	for _, definition := range []string{"function f0()", "function f2()"} {
+		if _, ok := cache.get(definition); !ok {
+			t.Errorf("expected %q to be cached", definition)
+		}
+	}
+	if cache.order.Len() != 2 || len(cache.entries) != 2 {
+		t.Errorf("expected 2 cached ABIs, got %d", len(cache.entries))
+	}
+}
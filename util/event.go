@@ -0,0 +1,202 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"sort"
+)
+
+// DecodedEvent is a log decoded against the ABI of its event
+type DecodedEvent struct {
+	types.Log
+
+	// Name is the event name as keyed in the ABI, overloaded events carry a numeric suffix
+	Name string
+	// Args holds the arguments by name, unnamed arguments are called arg0, arg1, ...
+	// Indexed strings, bytes, arrays and tuples only have their keccak256 hash as common.Hash
+	Args map[string]any
+	// Err is set instead of Args when a log returned by eth.Client.GetContractEvents does not fit the event
+	Err error
+}
+
+type decodedEventJSON struct {
+	Name string         `json:"eventName"`
+	Args map[string]any `json:"args"`
+	Err  string         `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes the event as its JSON-RPC log with the eventName, args and error fields added
+func (e DecodedEvent) MarshalJSON() ([]byte, error) {
+	log, err := json.Marshal(e.Log)
+	if err != nil {
+		return nil, err
+	}
+	dec := decodedEventJSON{Name: e.Name, Args: e.Args}
+	if e.Err != nil {
+		dec.Err = e.Err.Error()
+	}
+	event, err := json.Marshal(&dec)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(log, &fields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(event, &fields); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalJSON decodes an event encoded by MarshalJSON. Args hold the plain JSON values, numbers as json.Number
+func (e *DecodedEvent) UnmarshalJSON(input []byte) error {
+	var log types.Log
+	if err := json.Unmarshal(input, &log); err != nil {
+		return err
+	}
+	var dec decodedEventJSON
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	decoder.UseNumber()
+	if err := decoder.Decode(&dec); err != nil {
+		return err
+	}
+	*e = DecodedEvent{Log: log, Name: dec.Name, Args: dec.Args}
+	if dec.Err != "" {
+		e.Err = errors.New(dec.Err)
+	}
+	return nil
+}
+
+// EventTopics builds the topic filter of an event, query holds the accepted values per indexed argument
+// in order with nil matching any value. Anonymous events have no selector topic
+func EventTopics(contractABI *abi.ABI, eventName string, query ...[]any) ([][]common.Hash, error) {
+	event, ok := contractABI.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event %s: %w", eventName, verrors.ErrEventNotFound)
+	}
+	topics, err := abi.MakeTopics(query...)
+	if err != nil {
+		return nil, err
+	}
+	if event.Anonymous {
+		return topics, nil
+	}
+	return append([][]common.Hash{{event.ID}}, topics...), nil
+}
+
+// DecodeEventLog decodes a log against the events of contractABI. The event is selected by the first topic,
+// anonymous events have no selector and are tried in name order until one fits the topics and data of the log
+func DecodeEventLog(contractABI *abi.ABI, log types.Log) (*DecodedEvent, error) {
+	if len(log.Topics) > 0 {
+		for name, event := range contractABI.Events {
+			if !event.Anonymous && event.ID == log.Topics[0] {
+				return decodeEvent(name, event, log)
+			}
+		}
+	}
+	names := make([]string, 0, len(contractABI.Events))
+	for name := range contractABI.Events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if event := contractABI.Events[name]; event.Anonymous {
+			if decoded, err := decodeEvent(name, event, log); err == nil {
+				return decoded, nil
+			}
+		}
+	}
+	return nil, verrors.ErrEventNotFound
+}
+
+// DecodeEventLogByName decodes a log of the named event
+func DecodeEventLogByName(contractABI *abi.ABI, eventName string, log types.Log) (*DecodedEvent, error) {
+	event, ok := contractABI.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event %s: %w", eventName, verrors.ErrEventNotFound)
+	}
+	return decodeEvent(eventName, event, log)
+}
+
+// UnpackEventLog decodes a log of the named event into out, a pointer to a struct with a field per argument
+// named like the argument in camel case or tagged with `abi:"name"`. Fields of indexed dynamic types must be
+// common.Hash
+func UnpackEventLog(contractABI *abi.ABI, eventName string, log types.Log, out any) error {
+	event, ok := contractABI.Events[eventName]
+	if !ok {
+		return fmt.Errorf("event %s: %w", eventName, verrors.ErrEventNotFound)
+	}
+	decoded, err := decodeEvent(eventName, event, log)
+	if err != nil {
+		return err
+	}
+
+	// copy all arguments as if they were non-indexed so the struct mapping covers the topics as well
+	args := make(abi.Arguments, len(event.Inputs))
+	values := make([]any, len(event.Inputs))
+	for i, arg := range event.Inputs {
+		arg.Indexed = false
+		args[i] = arg
+		values[i] = decoded.Args[arg.Name]
+	}
+	if err := args.Copy(out, values); err != nil {
+		return fmt.Errorf("unpack event %s: %w", eventName, err)
+	}
+	return nil
+}
+
+func decodeEvent(name string, event abi.Event, log types.Log) (*DecodedEvent, error) {
+	topics, err := eventTopics(event, log)
+	if err != nil {
+		return nil, err
+	}
+	args := make(map[string]any, len(event.Inputs))
+	if err := event.Inputs.UnpackIntoMap(args, log.Data); err != nil {
+		return nil, fmt.Errorf("decode event %s: %w", name, err)
+	}
+	if err := parseTopics(event, topics, args); err != nil {
+		return nil, err
+	}
+	return &DecodedEvent{Log: log, Name: name, Args: args}, nil
+}
+
+// eventTopics returns the topics of the indexed arguments after checking they fit the event
+func eventTopics(event abi.Event, log types.Log) ([]common.Hash, error) {
+	topics := log.Topics
+	if !event.Anonymous {
+		if len(topics) == 0 || topics[0] != event.ID {
+			return nil, fmt.Errorf("decode event %s: signature topic mismatch", event.Name)
+		}
+		topics = topics[1:]
+	}
+	indexed := len(event.Inputs) - len(event.Inputs.NonIndexed())
+	if len(topics) != indexed {
+		return nil, fmt.Errorf("decode event %s: expected %d indexed topics, got %d", event.Name, indexed, len(topics))
+	}
+	return topics, nil
+}
+
+// parseTopics decodes the indexed arguments from their topics. Unlike abi.ParseTopics indexed tuples are
+// returned as their topic hash like all other dynamic types
+func parseTopics(event abi.Event, topics []common.Hash, args map[string]any) error {
+	var i int
+	for _, arg := range event.Inputs {
+		if !arg.Indexed {
+			continue
+		}
+		if arg.Type.T == abi.TupleTy {
+			args[arg.Name] = topics[i]
+		} else if err := abi.ParseTopicsIntoMap(args, abi.Arguments{arg}, topics[i:i+1]); err != nil {
+			return fmt.Errorf("decode event %s topic %s: %w", event.Name, arg.Name, err)
+		}
+		i++
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/AutoArbi/go-viem/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestParseHumanReadableABI(t *testing.T) {
+	parsed, err := ParseABI(`
+		function balanceOf(address owner) view returns (uint)
+		function swap((address tokenIn, uint24 fee)[] path, uint amountIn) payable returns (uint256 amountOut)
+		event Transfer(address indexed from, address indexed to, uint256 value)
+		error InsufficientBalance(uint256 available, uint256 required)
+		constructor(address owner)
+		receive() external payable
+	`)
+	if err != nil {
+		t.Fatalf("ParseABI error: %v", err)
+	}
+	if sig := parsed.Methods["balanceOf"].Sig; sig != "balanceOf(address)" {
+		t.Errorf("unexpected signature %s", sig)
+	}
+	swap := parsed.Methods["swap"]
+	if swap.Sig != "swap((address,uint24)[],uint256)" || !swap.IsPayable() || swap.Outputs[0].Name != "amountOut" {
+		t.Errorf("unexpected swap method: %s", swap)
+	}
+	if id := parsed.Events["Transfer"].ID; id != crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)")) {
+		t.Errorf("unexpected Transfer id %s", id.Hex())
+	}
+	if _, ok := parsed.Errors["InsufficientBalance"]; !ok {
+		t.Error("missing InsufficientBalance error")
+	}
+	if !parsed.HasReceive() || len(parsed.Constructor.Inputs) != 1 {
+		t.Error("missing constructor or receive")
+	}
+
+	if _, err := ParseHumanReadableABI("function broken(uint256"); err == nil {
+		t.Error("expected error for unbalanced signature")
+	}
+	if _, err := ParseHumanReadableABI("function f(uint256 indexed a)"); err == nil {
+		t.Error("expected error for indexed function parameter")
+	}
+}
+
+func TestDecodeEventLog(t *testing.T) {
+	parsed, err := ParseABI(`[
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		"event Named(string indexed name, (uint256 a, uint256 b) indexed pair, bytes data)",
+		"event Raw(uint256 indexed, uint256) anonymous"
+	]`)
+	if err != nil {
+		t.Fatalf("ParseABI error: %v", err)
+	}
+
+	from, to := common.HexToAddress("0x1"), common.HexToAddress("0x2")
+	transfer := parsed.Events["Transfer"]
+	data, _ := transfer.Inputs.NonIndexed().Pack(big.NewInt(42))
+	log := types.Log{
+		Topics: []common.Hash{transfer.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:   data,
+	}
+
+	decoded, err := DecodeEventLog(parsed, log)
+	if err != nil {
+		t.Fatalf("DecodeEventLog error: %v", err)
+	}
+	if decoded.Name != "Transfer" || decoded.Args["from"] != from || decoded.Args["to"] != to || decoded.Args["value"].(*big.Int).Int64() != 42 {
+		t.Errorf("unexpected decoded event: %+v", decoded)
+	}
+
+	var out struct {
+		From  common.Address
+		To    common.Address `abi:"to"`
+		Value *big.Int
+	}
+	if err := UnpackEventLog(parsed, "Transfer", log, &out); err != nil {
+		t.Fatalf("UnpackEventLog error: %v", err)
+	}
+	if out.From != from || out.To != to || out.Value.Int64() != 42 {
+		t.Errorf("unexpected unpacked event: %+v", out)
+	}
+
+	// indexed dynamic types decode to their topic hash
+	named := parsed.Events["Named"]
+	nameHash, pairHash := crypto.Keccak256Hash([]byte("pool")), common.HexToHash("0xabc")
+	data, _ = named.Inputs.NonIndexed().Pack([]byte{1, 2})
+	decoded, err = DecodeEventLog(parsed, types.Log{Topics: []common.Hash{named.ID, nameHash, pairHash}, Data: data})
+	if err != nil {
+		t.Fatalf("DecodeEventLog error: %v", err)
+	}
+	if decoded.Args["name"] != nameHash || decoded.Args["pair"] != pairHash {
+		t.Errorf("unexpected dynamic topics: %v", decoded.Args)
+	}
+
+	// anonymous events have no selector topic and get positional names
+	raw := parsed.Events["Raw"]
+	data, _ = raw.Inputs.NonIndexed().Pack(big.NewInt(7))
+	decoded, err = DecodeEventLog(parsed, types.Log{Topics: []common.Hash{common.BigToHash(big.NewInt(3))}, Data: data})
+	if err != nil {
+		t.Fatalf("DecodeEventLog error: %v", err)
+	}
+	if decoded.Name != "Raw" || decoded.Args["arg0"].(*big.Int).Int64() != 3 || decoded.Args["arg1"].(*big.Int).Int64() != 7 {
+		t.Errorf("unexpected anonymous event: %+v", decoded)
+	}
+
+	if _, err := DecodeEventLog(parsed, types.Log{Topics: []common.Hash{common.HexToHash("0xdead")}}); !errors.Is(err, verrors.ErrEventNotFound) {
+		t.Errorf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestDecodedEventJSON(t *testing.T) {
+	blockNumber, logIndex := big.NewInt(100), uint64(3)
+	event := DecodedEvent{
+		Log: types.Log{
+			Address:     common.HexToAddress("0x1"),
+			Topics:      []common.Hash{common.HexToHash("0xabc")},
+			Data:        []byte{0x01},
+			BlockNumber: blockNumber,
+			LogIndex:    &logIndex,
+		},
+		Name: "Transfer",
+		Args: map[string]any{"to": common.HexToAddress("0x2"), "value": new(big.Int).Lsh(big.NewInt(1), 100)},
+	}
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	var decoded DecodedEvent
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if decoded.Address != event.Address || decoded.BlockNumber.Cmp(blockNumber) != 0 || *decoded.LogIndex != logIndex ||
+		decoded.Name != "Transfer" || decoded.Err != nil {
+		t.Errorf("unexpected decoded event: %+v", decoded)
+	}
+	if decoded.Args["to"] != common.HexToAddress("0x2").Hex() || decoded.Args["value"] != json.Number("1267650600228229401496703205376") {
+		t.Errorf("unexpected args: %v", decoded.Args)
+	}
+
+	event.Args, event.Err = nil, errors.New("abi: cannot unmarshal")
+	encoded, _ = json.Marshal(&event)
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if decoded.Err == nil || decoded.Err.Error() != "abi: cannot unmarshal" || decoded.Args != nil {
+		t.Errorf("expected the error to round-trip, got %+v", decoded)
+	}
+}
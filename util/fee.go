@@ -0,0 +1,45 @@
+package util
+
+import (
+	"github.com/AutoArbi/go-viem/types"
+	"math"
+	"math/big"
+	"slices"
+)
+
+// feeMultiplierPrecision is the number of decimals of a fee multiplier that are taken into account
+const feeMultiplierPrecision = 1e9
+
+const (
+	// DefaultBaseFeeMultiplier scales the base fee of the latest block, or the gas price of legacy transactions,
+	// to leave room for fee increases until a transaction is included
+	DefaultBaseFeeMultiplier = 1.2
+	// PriorityFeeHistoryBlocks is the number of recent blocks the fallback priority fee is computed from
+	PriorityFeeHistoryBlocks = 5
+	// PriorityFeePercentile is the reward percentile of each block the fallback priority fee is computed from
+	PriorityFeePercentile = 50
+)
+
+// MultiplyFee multiplies a fee with a decimal multiplier like 1.2, rounding down
+func MultiplyFee(fee *big.Int, multiplier float64) *big.Int {
+	scaled := new(big.Int).Mul(fee, big.NewInt(int64(math.Round(multiplier*feeMultiplierPrecision))))
+	return scaled.Quo(scaled, big.NewInt(feeMultiplierPrecision))
+}
+
+// MedianPriorityFee returns the median of the first reward percentile of the blocks in a fee history, the fallback
+// priority fee of nodes without eth_maxPriorityFeePerGas when the history is requested for PriorityFeeHistoryBlocks
+// at PriorityFeePercentile. Empty blocks are ignored and zero is returned when all blocks are empty
+func MedianPriorityFee(history *types.FeeHistoryResult) *big.Int {
+	var rewards []*big.Int
+	for i, reward := range history.Reward {
+		if len(reward) == 0 || reward[0] == nil || i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0 {
+			continue
+		}
+		rewards = append(rewards, reward[0])
+	}
+	if len(rewards) == 0 {
+		return new(big.Int)
+	}
+	slices.SortFunc(rewards, func(a, b *big.Int) int { return a.Cmp(b) })
+	return new(big.Int).Set(rewards[len(rewards)/2])
+}
@@ -0,0 +1,148 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"strings"
+)
+
+var (
+	// errorSelector is the selector of require and revert with a message
+	errorSelector = [4]byte{0x08, 0xc3, 0x79, 0xa0}
+	// panicSelector is the selector of failed asserts and checked arithmetic
+	panicSelector = [4]byte{0x4e, 0x48, 0x7b, 0x71}
+
+	errorArgs = abi.Arguments{{Type: mustNewType("string")}}
+	panicArgs = abi.Arguments{{Type: mustNewType("uint256")}}
+)
+
+// PanicReasons maps the Panic(uint256) codes the Solidity compiler emits to their meaning
+var PanicReasons = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "conversion into non-existent enum type",
+	0x22: "access to incorrectly encoded storage byte array",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized variable of internal function type",
+}
+
+// ContractRevertError is returned for contract calls that reverted
+type ContractRevertError struct {
+	// Name is "Error" for reverts with a message, "Panic" for panics and the error name for custom errors.
+	// It is empty when the revert data is empty or its selector is unknown
+	Name string
+	// Args are the decoded error arguments in order
+	Args []any
+	// Selector is the first 4 bytes of the revert data
+	Selector [4]byte
+	// Reason is the message of Error(string) reverts or the meaning of the panic code
+	Reason string
+	// Data is the raw revert data
+	Data []byte
+}
+
+func (e *ContractRevertError) Error() string {
+	switch {
+	case e.Reason != "":
+		return fmt.Sprintf("execution reverted: %s", e.Reason)
+	case e.Name != "":
+		args := make([]string, len(e.Args))
+		for i, arg := range e.Args {
+			args[i] = fmt.Sprint(arg)
+		}
+		return fmt.Sprintf("execution reverted: %s(%s)", e.Name, strings.Join(args, ", "))
+	case len(e.Data) >= 4:
+		return fmt.Sprintf("execution reverted with unknown error %s, data %s", hexutil.Encode(e.Selector[:]), hexutil.Encode(e.Data))
+	case len(e.Data) > 0:
+		return fmt.Sprintf("execution reverted with data %s", hexutil.Encode(e.Data))
+	}
+	return "execution reverted"
+}
+
+// PanicCode returns the code of a Panic(uint256) revert
+func (e *ContractRevertError) PanicCode() (*big.Int, bool) {
+	if e.Name != "Panic" || len(e.Args) != 1 {
+		return nil, false
+	}
+	code, ok := e.Args[0].(*big.Int)
+	return code, ok
+}
+
+// DecodeRevert decodes the revert data of a failed call. Error(string) and Panic(uint256) are always decoded,
+// custom errors are looked up in abis in order. Data with an unknown selector only sets Selector and Data
+func DecodeRevert(data []byte, abis ...*abi.ABI) *ContractRevertError {
+	revertErr := &ContractRevertError{Data: data}
+	if len(data) < 4 {
+		return revertErr
+	}
+	copy(revertErr.Selector[:], data[:4])
+
+	switch revertErr.Selector {
+	case errorSelector:
+		if values, err := errorArgs.Unpack(data[4:]); err == nil {
+			revertErr.Name = "Error"
+			revertErr.Args = values
+			revertErr.Reason = values[0].(string)
+		}
+	case panicSelector:
+		if values, err := panicArgs.Unpack(data[4:]); err == nil {
+			code := values[0].(*big.Int)
+			revertErr.Name = "Panic"
+			revertErr.Args = values
+			revertErr.Reason = fmt.Sprintf("panic code %#x", code)
+			if reason, ok := PanicReasons[code.Uint64()]; ok && code.IsUint64() {
+				revertErr.Reason = fmt.Sprintf("%s (panic code %#x)", reason, code)
+			}
+		}
+	default:
+		for _, contractABI := range abis {
+			customErr, err := contractABI.ErrorByID(revertErr.Selector)
+			if err != nil {
+				continue
+			}
+			values, err := customErr.Inputs.Unpack(data[4:])
+			if err != nil {
+				continue
+			}
+			revertErr.Name = customErr.Name
+			revertErr.Args = values
+			break
+		}
+	}
+	return revertErr
+}
+
+// RevertFromError turns the error a node returned for a reverted call or gas estimation into a *ContractRevertError
+// with the revert data decoded against abis. It returns nil for other errors
+func RevertFromError(err error, abis ...*abi.ABI) *ContractRevertError {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(hexData); decodeErr == nil {
+				return DecodeRevert(data, abis...)
+			}
+		}
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && strings.Contains(rpcErr.Error(), "execution reverted") {
+		reason := strings.TrimPrefix(strings.TrimPrefix(rpcErr.Error(), "execution reverted"), ": ")
+		return &ContractRevertError{Reason: reason}
+	}
+	return nil
+}
+
+func mustNewType(t string) abi.Type {
+	typ, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return typ
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestDecodeRevert(t *testing.T) {
+	routerABI, err := ParseABI(`error InsufficientOutputAmount(uint256 amountOut, uint256 amountOutMin)
+error Expired(uint256 deadline)`)
+	if err != nil {
+		t.Fatalf("ParseABI error: %v", err)
+	}
+	pairABI, _ := ParseABI(`error Locked(address pair)`)
+
+	// revert with a message
+	reason := hexutil.MustDecode("0x08c379a0" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6f6f707300000000000000000000000000000000000000000000000000000000")
+	if revertErr := DecodeRevert(reason); revertErr.Name != "Error" || revertErr.Reason != "oops" {
+		t.Errorf("unexpected Error(string) decoding: %+v", revertErr)
+	}
+
+	// checked arithmetic panic
+	panicData := hexutil.MustDecode("0x4e487b71" + "0000000000000000000000000000000000000000000000000000000000000011")
+	revertErr := DecodeRevert(panicData)
+	code, ok := revertErr.PanicCode()
+	if !ok || code.Int64() != 0x11 || revertErr.Reason != "arithmetic underflow or overflow (panic code 0x11)" {
+		t.Errorf("unexpected Panic(uint256) decoding: %+v", revertErr)
+	}
+	if got, err := RevertReason(hexutil.Encode(panicData)); err != nil || got != revertErr.Reason {
+		t.Errorf("RevertReason = %q, %v", got, err)
+	}
+
+	// custom errors are looked up in all ABIs
+	customErr := routerABI.Errors["InsufficientOutputAmount"]
+	args, _ := customErr.Inputs.Pack(big.NewInt(90), big.NewInt(100))
+	custom := append(customErr.ID[:4:4], args...)
+	revertErr = DecodeRevert(custom, pairABI, routerABI)
+	if revertErr.Name != "InsufficientOutputAmount" || len(revertErr.Args) != 2 || revertErr.Args[1].(*big.Int).Int64() != 100 {
+		t.Errorf("unexpected custom error decoding: %+v", revertErr)
+	}
+	if revertErr.Error() != "execution reverted: InsufficientOutputAmount(90, 100)" {
+		t.Errorf("unexpected message: %s", revertErr.Error())
+	}
+	lockedErr := pairABI.Errors["Locked"]
+	args, _ = lockedErr.Inputs.Pack(common.HexToAddress("0x1"))
+	if revertErr := DecodeRevert(append(lockedErr.ID[:4:4], args...), routerABI, pairABI); revertErr.Name != "Locked" {
+		t.Errorf("unexpected custom error decoding: %+v", revertErr)
+	}
+
+	// unknown selectors keep the raw data
+	unknown := hexutil.MustDecode("0xdeadbeef01")
+	revertErr = DecodeRevert(unknown, routerABI)
+	if revertErr.Name != "" || revertErr.Selector != [4]byte{0xde, 0xad, 0xbe, 0xef} || len(revertErr.Data) != 5 {
+		t.Errorf("unexpected unknown error decoding: %+v", revertErr)
+	}
+	if _, err := RevertReason(hexutil.Encode(unknown)); err == nil {
+		t.Error("expected RevertReason error for custom error data")
+	}
+}
@@ -0,0 +1,85 @@
+package util
+
+import (
+	"fmt"
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// HashMessage returns the EIP-191 personal message hash that SignMessage signs
+func HashMessage(message []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(message))
+}
+
+// NormalizeSignature returns the 65 byte r || s || v form with v 0 or 1 that go-ethereum's crypto package expects.
+// It accepts 65 byte signatures with v 0, 1, 27 or 28 and 64 byte EIP-2098 compact signatures, whose highest
+// bit of s is the y parity
+func NormalizeSignature(signature []byte) ([]byte, error) {
+	normalized := make([]byte, crypto.SignatureLength)
+	switch len(signature) {
+	case crypto.SignatureLength:
+		copy(normalized, signature)
+		v := signature[crypto.RecoveryIDOffset]
+		if v >= 27 {
+			v -= 27
+		}
+		if v > 1 {
+			return nil, fmt.Errorf("%w: v of %d", verrors.ErrInvalidSignature, signature[crypto.RecoveryIDOffset])
+		}
+		normalized[crypto.RecoveryIDOffset] = v
+	case crypto.SignatureLength - 1:
+		copy(normalized, signature)
+		normalized[crypto.RecoveryIDOffset] = signature[32] >> 7
+		normalized[32] &= 0x7f
+	default:
+		return nil, fmt.Errorf("%w: length of %d bytes", verrors.ErrInvalidSignature, len(signature))
+	}
+	return normalized, nil
+}
+
+// RecoverAddress returns the address that signed the hash
+func RecoverAddress(hash common.Hash, signature []byte) (common.Address, error) {
+	normalized, err := NormalizeSignature(signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	pub, err := crypto.SigToPub(hash.Bytes(), normalized)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", verrors.ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// RecoverMessageAddress returns the address that signed an EIP-191 personal message
+func RecoverMessageAddress(message, signature []byte) (common.Address, error) {
+	return RecoverAddress(HashMessage(message), signature)
+}
+
+// RecoverTypedDataAddress returns the address that signed EIP-712 typed data given as JSON
+func RecoverTypedDataAddress(typedDataJSON string, signature []byte) (common.Address, error) {
+	hash, err := TypedDataHash(typedDataJSON)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return RecoverAddress(hash, signature)
+}
+
+// VerifyMessage reports whether address signed the EIP-191 personal message, malformed signatures are reported
+// as not valid
+func VerifyMessage(address common.Address, message, signature []byte) bool {
+	signer, err := RecoverMessageAddress(message, signature)
+	return err == nil && signer == address
+}
+
+// VerifyTypedData reports whether address signed the EIP-712 typed data. The error is only returned for
+// invalid typed data, malformed signatures are reported as not valid
+func VerifyTypedData(address common.Address, typedDataJSON string, signature []byte) (bool, error) {
+	hash, err := TypedDataHash(typedDataJSON)
+	if err != nil {
+		return false, err
+	}
+	signer, err := RecoverAddress(hash, signature)
+	return err == nil && signer == address, nil
+}
@@ -0,0 +1,105 @@
+package util
+
+import (
+	"errors"
+	"testing"
+
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [{"name": "name", "type": "string"}, {"name": "wallet", "type": "address"}],
+		"Mail": [{"name": "from", "type": "Person"}, {"name": "to", "type": "Person"}, {"name": "contents", "type": "string"}]
+	},
+	"primaryType": "Mail",
+	"domain": {"name": "Ether Mail", "version": "1", "chainId": "1", "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestRecoverMessageAddress_EIP2098(t *testing.T) {
+	// test vectors of EIP-2098, signed as personal messages
+	key, _ := crypto.HexToECDSA("1234567890123456789012345678901234567890123456789012345678901234")
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	tests := []struct {
+		message, full, compact string
+	}{
+		{
+			"Hello World",
+			"0x68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea520641b",
+			"0x68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b907e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064",
+		},
+		{
+			"It's a small(er) world",
+			"0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76139c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f5507931c",
+			"0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793",
+		},
+	}
+	for i, tt := range tests {
+		for _, signature := range []string{tt.full, tt.compact} {
+			address, err := RecoverMessageAddress([]byte(tt.message), hexutil.MustDecode(signature))
+			if err != nil || address != signer {
+				t.Errorf("case %d: expected %s, got %s (%v)", i, signer, address, err)
+			}
+		}
+	}
+}
+
+func TestVerifySignatures(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	message := []byte("order 42")
+
+	signature, err := crypto.Sign(HashMessage(message).Bytes(), key)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	withV27 := append([]byte{}, signature...)
+	withV27[64] += 27
+	for _, sig := range [][]byte{signature, withV27} {
+		if !VerifyMessage(signer, message, sig) {
+			t.Errorf("expected valid message signature with v %d", sig[64])
+		}
+	}
+	if VerifyMessage(signer, []byte("order 43"), signature) {
+		t.Error("expected invalid signature for another message")
+	}
+	if VerifyMessage(common.HexToAddress("0x1"), message, signature) {
+		t.Error("expected invalid signature for another signer")
+	}
+
+	hash, err := TypedDataHash(mailTypedData)
+	if err != nil {
+		t.Fatalf("TypedDataHash failed: %v", err)
+	}
+	typedSignature, _ := crypto.Sign(hash.Bytes(), key)
+	typedSignature[64] += 27
+	if address, err := RecoverTypedDataAddress(mailTypedData, typedSignature); err != nil || address != signer {
+		t.Errorf("expected typed data signer %s, got %s (%v)", signer, address, err)
+	}
+	if valid, err := VerifyTypedData(signer, mailTypedData, typedSignature[:63]); err != nil || valid {
+		t.Errorf("expected truncated signature to be invalid, got %v (%v)", valid, err)
+	}
+	if _, err := VerifyTypedData(signer, `{"types": `, typedSignature); err == nil {
+		t.Error("expected error for invalid typed data")
+	}
+
+	badV := append([]byte{}, signature...)
+	badV[64] = 29
+	if _, err := RecoverAddress(HashMessage(message), badV); !errors.Is(err, verrors.ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for v 29, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package wallet
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"strings"
+)
+
+// HardenedOffset is added to the index of hardened BIP-32 children, written as ' in paths
+const HardenedOffset uint32 = 0x80000000
+
+// DefaultDerivationPath is the BIP-44 path of the first Ethereum account, m/44'/60'/0'/0/0
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+// errInvalidHDKey is returned for the rare indices that derive an invalid key, BIP-32 skips to the next index
+var errInvalidHDKey = errors.New("derived key is invalid, use the next index")
+
+// HDKey is a BIP-32 extended private key
+type HDKey struct {
+	privateKey []byte
+	chainCode  []byte
+}
+
+// HDOptions selects the BIP-44 path m/44'/60'/AccountIndex'/ChangeIndex/AddressIndex of an HD account, or a custom Path
+type HDOptions struct {
+	AccountIndex uint32
+	ChangeIndex  uint32
+	AddressIndex uint32
+	// Path replaces the BIP-44 path, e.g. "m/44'/60'/0'/0/5"
+	Path string
+	// Passphrase is the optional BIP-39 passphrase of the mnemonic
+	Passphrase string
+}
+
+// path returns the derivation path of the options
+func (o *HDOptions) path() string {
+	if o.Path != "" {
+		return o.Path
+	}
+	return fmt.Sprintf("m/44'/60'/%d'/%d/%d", o.AccountIndex, o.ChangeIndex, o.AddressIndex)
+}
+
+// NewMasterKey creates the BIP-32 master key of a seed
+func NewMasterKey(seed []byte) (*HDKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("invalid seed length %d, must be between 16 and 64 bytes", len(seed))
+	}
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	if !validHDKey(new(big.Int).SetBytes(sum[:32])) {
+		return nil, errInvalidHDKey
+	}
+	return &HDKey{privateKey: sum[:32], chainCode: sum[32:]}, nil
+}
+
+// MnemonicToHDKey creates the BIP-32 master key of a mnemonic of any wordlist and an optional passphrase
+func MnemonicToHDKey(mnemonic, passphrase string) (*HDKey, error) {
+	var err error
+	for _, wordlist := range Wordlists {
+		if err = ValidateMnemonic(mnemonic, wordlist); err == nil {
+			return NewMasterKey(MnemonicToSeed(mnemonic, passphrase))
+		}
+		if errors.Is(err, errMnemonicWordCount) || errors.Is(err, errMnemonicChecksum) {
+			return nil, fmt.Errorf("invalid mnemonic: %w", err)
+		}
+	}
+	return nil, errors.New("invalid mnemonic: words are not in any wordlist")
+}
+
+// Derive derives the key at an absolute path like "m/44'/60'/0'/0/0" from the master key
+func (k *HDKey) Derive(path string) (*HDKey, error) {
+	path = strings.TrimSpace(path)
+	if path == "m" {
+		return k, nil
+	}
+	if !strings.HasPrefix(path, "m/") {
+		return nil, fmt.Errorf("derivation path %q must start with m/", path)
+	}
+	indices, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	key := k
+	for _, index := range indices {
+		if key, err = key.Child(index); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Child derives the child key at index, indices from HardenedOffset on are hardened
+func (k *HDKey) Child(index uint32) (*HDKey, error) {
+	mac := hmac.New(sha512.New, k.chainCode)
+	if index >= HardenedOffset {
+		mac.Write([]byte{0})
+		mac.Write(k.privateKey)
+	} else {
+		privateKey, err := crypto.ToECDSA(k.privateKey)
+		if err != nil {
+			return nil, err
+		}
+		mac.Write(crypto.CompressPubkey(&privateKey.PublicKey))
+	}
+	var indexBytes [4]byte
+	binary.BigEndian.PutUint32(indexBytes[:], index)
+	mac.Write(indexBytes[:])
+	sum := mac.Sum(nil)
+
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(crypto.S256().Params().N) >= 0 {
+		return nil, errInvalidHDKey
+	}
+	child := tweak.Add(tweak, new(big.Int).SetBytes(k.privateKey))
+	child.Mod(child, crypto.S256().Params().N)
+	if !validHDKey(child) {
+		return nil, errInvalidHDKey
+	}
+	return &HDKey{privateKey: child.FillBytes(make([]byte, 32)), chainCode: sum[32:]}, nil
+}
+
+// Account returns the account of the key
+func (k *HDKey) Account() (*PrivateKeyAccount, error) {
+	privateKey, err := crypto.ToECDSA(k.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKeyAccount(privateKey), nil
+}
+
+// MnemonicToAccount derives the account at the BIP-44 path of opts from a mnemonic, default is DefaultDerivationPath.
+// To derive many accounts create the master key once with MnemonicToHDKey and use HDKeyToAccount
+func MnemonicToAccount(mnemonic string, opts *HDOptions) (*PrivateKeyAccount, error) {
+	if opts == nil {
+		opts = &HDOptions{}
+	}
+	key, err := MnemonicToHDKey(mnemonic, opts.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return HDKeyToAccount(key, opts)
+}
+
+// HDKeyToAccount derives the account at the BIP-44 path of opts from a master key, default is DefaultDerivationPath
+func HDKeyToAccount(key *HDKey, opts *HDOptions) (*PrivateKeyAccount, error) {
+	if opts == nil {
+		opts = &HDOptions{}
+	}
+	child, err := key.Derive(opts.path())
+	if err != nil {
+		return nil, err
+	}
+	return child.Account()
+}
+
+// validHDKey reports whether a derived key is a valid secp256k1 private key
+func validHDKey(key *big.Int) bool {
+	return key.Sign() > 0 && key.Cmp(crypto.S256().Params().N) < 0
+}
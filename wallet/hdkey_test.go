@@ -0,0 +1,68 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestHDKeyDerive(t *testing.T) {
+	// BIP-32 test vector 1
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := NewMasterKey(seed)
+	if err != nil {
+		t.Fatalf("NewMasterKey failed: %v", err)
+	}
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"},
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, tt := range tests {
+		key, err := master.Derive(tt.path)
+		if err != nil {
+			t.Errorf("%s: Derive failed: %v", tt.path, err)
+			continue
+		}
+		if got := hex.EncodeToString(key.privateKey); got != tt.key {
+			t.Errorf("%s: expected key %s, got %s", tt.path, tt.key, got)
+		}
+	}
+
+	if _, err := master.Derive("0/1"); err == nil {
+		t.Error("expected error for a relative path")
+	}
+}
+
+func TestMnemonicToAccount(t *testing.T) {
+	const mnemonic = "test test test test test test test test test test test junk"
+	account, err := MnemonicToAccount(mnemonic, nil)
+	if err != nil {
+		t.Fatalf("MnemonicToAccount failed: %v", err)
+	}
+	if want := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"); account.Address() != want {
+		t.Errorf("expected address %s, got %s", want, account.Address())
+	}
+
+	key, err := MnemonicToHDKey(mnemonic, "")
+	if err != nil {
+		t.Fatalf("MnemonicToHDKey failed: %v", err)
+	}
+	for _, opts := range []*HDOptions{{AddressIndex: 1}, {Path: "m/44'/60'/0'/0/1"}} {
+		account, err := HDKeyToAccount(key, opts)
+		if err != nil {
+			t.Fatalf("HDKeyToAccount failed: %v", err)
+		}
+		if want := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8"); account.Address() != want {
+			t.Errorf("expected address %s, got %s", want, account.Address())
+		}
+	}
+
+	if _, err := MnemonicToAccount("test test test test test test test test test test test test", nil); err == nil {
+		t.Error("expected error for a mnemonic with an invalid checksum")
+	}
+}
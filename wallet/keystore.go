@@ -0,0 +1,247 @@
+package wallet
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+const (
+	// StandardScryptN and StandardScryptP are the scrypt parameters geth uses for new keystores
+	StandardScryptN = keystore.StandardScryptN
+	StandardScryptP = keystore.StandardScryptP
+	// LightScryptN and LightScryptP use less memory and CPU, for tests and constrained hosts
+	LightScryptN = keystore.LightScryptN
+	LightScryptP = keystore.LightScryptP
+
+	keystoreVersion   = 3
+	keystoreDKLen     = 32
+	keystoreScryptR   = 8
+	keystoreKDFScrypt = "scrypt"
+	keystoreKDFPBKDF2 = "pbkdf2"
+
+	// the kdf parameters of keystores are bounded, so a crafted file cannot make loading use gigabytes of memory
+	// or hang. scrypt needs 128 * n * r bytes, at most 1 GiB
+	maxScryptN          = 1 << 20
+	maxScryptR          = 8
+	maxScryptP          = 16
+	maxPBKDF2Iterations = 1 << 23
+	maxKeystoreDKLen    = 64
+)
+
+// KeystoreOptions configures the encryption of new keystores, which use scrypt like geth and Foundry
+type KeystoreOptions struct {
+	// ScryptN and ScryptP default to StandardScryptN and StandardScryptP
+	ScryptN int
+	ScryptP int
+}
+
+type keystoreJSON struct {
+	Address string              `json:"address,omitempty"`
+	Crypto  keystore.CryptoJSON `json:"crypto"`
+	ID      string              `json:"id"`
+	Version int                 `json:"version"`
+}
+
+// LoadKeystoreAccount decrypts the V3 keystore file at path and returns its account, which signs for a client
+// through client.WithAccount
+func LoadKeystoreAccount(path, password string) (*PrivateKeyAccount, error) {
+	keystore, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeystoreAccount(keystore, password)
+}
+
+// NewKeystoreAccount decrypts a V3 keystore, as written by geth, Foundry or viem, and returns its account
+func NewKeystoreAccount(keystore []byte, password string) (*PrivateKeyAccount, error) {
+	privateKey, err := DecryptKeystore(keystore, password)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKeyAccount(privateKey), nil
+}
+
+// CreateKeystoreAccount generates a new private key and returns its account and its keystore encrypted with password
+func CreateKeystoreAccount(password string, opts *KeystoreOptions) (*PrivateKeyAccount, []byte, error) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	account := NewPrivateKeyAccount(privateKey)
+	keystore, err := account.ExportKeystore(password, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return account, keystore, nil
+}
+
+// ExportKeystore encrypts the private key of the account with password into a V3 keystore
+func (a *PrivateKeyAccount) ExportKeystore(password string, opts *KeystoreOptions) ([]byte, error) {
+	return EncryptKeystore(a.privateKey, password, opts)
+}
+
+// EncryptKeystore encrypts a private key with password into a V3 keystore
+func EncryptKeystore(privateKey *ecdsa.PrivateKey, password string, opts *KeystoreOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &KeystoreOptions{}
+	}
+	n, p := opts.ScryptN, opts.ScryptP
+	if n == 0 {
+		n = StandardScryptN
+	}
+	if p == 0 {
+		p = StandardScryptP
+	}
+	if err := checkScryptParams(n, keystoreScryptR, p); err != nil {
+		return nil, err
+	}
+
+	cryptoJSON, err := keystore.EncryptDataV3(crypto.FromECDSA(privateKey), []byte(password), n, p)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newUUID()
+	if err != nil {
+		return nil, err
+	}
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+	return json.Marshal(&keystoreJSON{
+		Address: hex.EncodeToString(address[:]),
+		Crypto:  cryptoJSON,
+		ID:      id,
+		Version: keystoreVersion,
+	})
+}
+
+// DecryptKeystore decrypts the private key of a V3 keystore with the scrypt or pbkdf2 kdf, errors.ErrKeystorePassword
+// is returned for a wrong password
+func DecryptKeystore(keyJSON []byte, password string) (*ecdsa.PrivateKey, error) {
+	var k keystoreJSON
+	if err := json.Unmarshal(keyJSON, &k); err != nil {
+		return nil, fmt.Errorf("invalid keystore: %w", err)
+	}
+	if k.Version != keystoreVersion {
+		return nil, fmt.Errorf("unsupported keystore version %d", k.Version)
+	}
+	if err := checkKDFParams(k.Crypto.KDF, k.Crypto.KDFParams); err != nil {
+		return nil, err
+	}
+
+	plainText, err := keystore.DecryptDataV3(k.Crypto, password)
+	if errors.Is(err, keystore.ErrDecrypt) {
+		return nil, verrors.ErrKeystorePassword
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore: %w", err)
+	}
+	// some encoders strip leading zero bytes of the key
+	privateKey, err := crypto.ToECDSA(common.LeftPadBytes(plainText, 32))
+	if err != nil {
+		return nil, fmt.Errorf("invalid keystore private key: %w", err)
+	}
+	if k.Address != "" && !strings.EqualFold(strings.TrimPrefix(k.Address, "0x"), hex.EncodeToString(crypto.PubkeyToAddress(privateKey.PublicKey).Bytes())) {
+		return nil, errors.New("keystore address does not match its private key")
+	}
+	return privateKey, nil
+}
+
+// checkKDFParams checks the kdf parameters of a keystore against the limits before they are derived from, go-ethereum
+// panics on missing parameters and derives with any cost
+func checkKDFParams(kdf string, params map[string]interface{}) error {
+	if _, ok := params["salt"].(string); !ok {
+		return errors.New("keystore kdf parameter salt is missing")
+	}
+	dkLen, err := kdfParamInt(params, "dklen")
+	if err != nil {
+		return err
+	}
+	if dkLen < keystoreDKLen || dkLen > maxKeystoreDKLen {
+		return fmt.Errorf("keystore dklen %d is out of range %d to %d", dkLen, keystoreDKLen, maxKeystoreDKLen)
+	}
+
+	switch kdf {
+	case keystoreKDFScrypt:
+		n, err := kdfParamInt(params, "n")
+		if err != nil {
+			return err
+		}
+		r, err := kdfParamInt(params, "r")
+		if err != nil {
+			return err
+		}
+		p, err := kdfParamInt(params, "p")
+		if err != nil {
+			return err
+		}
+		return checkScryptParams(n, r, p)
+	case keystoreKDFPBKDF2:
+		if _, ok := params["prf"].(string); !ok {
+			return errors.New("keystore kdf parameter prf is missing")
+		}
+		c, err := kdfParamInt(params, "c")
+		if err != nil {
+			return err
+		}
+		if c > maxPBKDF2Iterations {
+			return fmt.Errorf("keystore pbkdf2 iteration count %d exceeds %d", c, maxPBKDF2Iterations)
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported keystore kdf %q", kdf)
+}
+
+// checkScryptParams checks the scrypt parameters against the limits
+func checkScryptParams(n, r, p int) error {
+	if n < 2 || n > maxScryptN || n&(n-1) != 0 {
+		return fmt.Errorf("keystore scrypt n %d must be a power of 2 up to %d", n, maxScryptN)
+	}
+	if r < 1 || r > maxScryptR {
+		return fmt.Errorf("keystore scrypt r %d is out of range 1 to %d", r, maxScryptR)
+	}
+	if p < 1 || p > maxScryptP {
+		return fmt.Errorf("keystore scrypt p %d is out of range 1 to %d", p, maxScryptP)
+	}
+	return nil
+}
+
+// kdfParamInt returns a positive integer kdf parameter, decoded JSON numbers are float64
+func kdfParamInt(params map[string]interface{}, name string) (int, error) {
+	var value float64
+	switch v := params[name].(type) {
+	case nil:
+		return 0, fmt.Errorf("keystore kdf parameter %s is missing", name)
+	case float64:
+		value = v
+	case int:
+		value = float64(v)
+	default:
+		return 0, fmt.Errorf("keystore kdf parameter %s is not a number", name)
+	}
+	if value < 1 || value > math.MaxInt32 || value != math.Trunc(value) {
+		return 0, fmt.Errorf("keystore kdf parameter %s %v is not a positive integer", name, value)
+	}
+	return int(value), nil
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() (string, error) {
+	var u [16]byte
+	if _, err := io.ReadFull(rand.Reader, u[:]); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+}
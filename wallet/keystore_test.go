@@ -0,0 +1,120 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	verrors "github.com/AutoArbi/go-viem/errors"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keystore test vectors of the Web3 Secret Storage definition and geth
+var keystoreVectors = []struct {
+	name     string
+	keystore string
+	password string
+	priv     string
+}{
+	{
+		name:     "pbkdf2",
+		keystore: `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2","kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		password: "testpassword",
+		priv:     "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d",
+	},
+	{
+		name:     "scrypt",
+		keystore: `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"83dbcc02d8ccb40e466191a123791e0e"},"ciphertext":"d172bf743a674da9cdad04534d56926ef8358534d458fffccd4e6ad2fbde479c","kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"r":1,"p":8,"salt":"ab0c7876052600dd703518d6fc3fe8984592145b591fc8fb5c6d43190334ba19"},"mac":"2103ac29920d71da29f15d75b4a16dbe95cfd7ff8faea1056c33131d846e3097"},"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`,
+		password: "testpassword",
+		priv:     "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d",
+	},
+	{
+		name:     "31 byte key",
+		keystore: `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"e0c41130a323adc1446fc82f724bca2f"},"ciphertext":"9517cd5bdbe69076f9bf5057248c6c050141e970efa36ce53692d5d59a3984","kdf":"scrypt","kdfparams":{"dklen":32,"n":2,"r":8,"p":1,"salt":"711f816911c92d649fb4c84b047915679933555030b3552c1212609b38208c63"},"mac":"d5e116151c6aa71470e67a7d42c9620c75c4d23229847dcc127794f0732b0db5"},"id":"fecfc4ce-e956-48fd-953b-30f8b52ed66c","version":3}`,
+		password: "foo",
+		priv:     "00fa7b3db73dc7dfdf8c5fbdb796d741e4488628c41fc4febd9160a866ba0f35",
+	},
+}
+
+func TestDecryptKeystore(t *testing.T) {
+	for _, v := range keystoreVectors {
+		privateKey, err := DecryptKeystore([]byte(v.keystore), v.password)
+		if err != nil {
+			t.Errorf("%s: DecryptKeystore failed: %v", v.name, err)
+			continue
+		}
+		if got := hex.EncodeToString(crypto.FromECDSA(privateKey)); got != v.priv {
+			t.Errorf("%s: expected key %s, got %s", v.name, v.priv, got)
+		}
+	}
+
+	if _, err := DecryptKeystore([]byte(keystoreVectors[2].keystore), "bar"); !errors.Is(err, verrors.ErrKeystorePassword) {
+		t.Errorf("expected ErrKeystorePassword, got %v", err)
+	}
+}
+
+func TestKeystoreRoundTrip(t *testing.T) {
+	for _, opts := range []*KeystoreOptions{
+		{ScryptN: LightScryptN, ScryptP: LightScryptP},
+		{ScryptN: 2, ScryptP: 1},
+	} {
+		account, keystore, err := CreateKeystoreAccount("secret", opts)
+		if err != nil {
+			t.Fatalf("CreateKeystoreAccount failed: %v", err)
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(keystore, &decoded); err != nil || decoded["version"] != float64(3) || decoded["address"] != hex.EncodeToString(account.Address().Bytes()) {
+			t.Errorf("unexpected keystore: %s (%v)", keystore, err)
+		}
+
+		imported, err := NewKeystoreAccount(keystore, "secret")
+		if err != nil {
+			t.Fatalf("NewKeystoreAccount failed: %v", err)
+		}
+		if imported.Address() != account.Address() {
+			t.Errorf("expected address %s, got %s", account.Address(), imported.Address())
+		}
+	}
+}
+
+func TestDecryptKeystore_KDFLimits(t *testing.T) {
+	var keystore map[string]any
+	if err := json.Unmarshal([]byte(keystoreVectors[2].keystore), &keystore); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	kdfParams := keystore["crypto"].(map[string]any)["kdfparams"].(map[string]any)
+	for name, value := range map[string]any{
+		"n":     float64(1 << 30),
+		"r":     float64(1 << 20),
+		"p":     float64(1 << 20),
+		"dklen": float64(1 << 30),
+	} {
+		original := kdfParams[name]
+		for _, v := range []any{value, nil, float64(0), -1.5} {
+			if v == nil {
+				delete(kdfParams, name)
+			} else {
+				kdfParams[name] = v
+			}
+			crafted, _ := json.Marshal(keystore)
+			if _, err := DecryptKeystore(crafted, keystoreVectors[2].password); err == nil || errors.Is(err, verrors.ErrKeystorePassword) {
+				t.Errorf("expected %s %v to be rejected, got %v", name, v, err)
+			}
+		}
+		kdfParams[name] = original
+	}
+
+	// go-ethereum panics on a missing salt
+	delete(kdfParams, "salt")
+	crafted, _ := json.Marshal(keystore)
+	if _, err := DecryptKeystore(crafted, keystoreVectors[2].password); err == nil {
+		t.Error("expected a keystore without salt to be rejected")
+	}
+
+	pbkdf2Keystore := strings.Replace(keystoreVectors[0].keystore, `"c":262144`, `"c":1e12`, 1)
+	if _, err := DecryptKeystore([]byte(pbkdf2Keystore), keystoreVectors[0].password); err == nil || errors.Is(err, verrors.ErrKeystorePassword) {
+		t.Errorf("expected a huge pbkdf2 iteration count to be rejected, got %v", err)
+	}
+}
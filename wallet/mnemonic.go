@@ -0,0 +1,145 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"embed"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+	"math/big"
+	"strings"
+)
+
+//go:embed wordlists/*.txt
+var wordlistFiles embed.FS
+
+// Wordlist is a BIP-39 list of 2048 words
+type Wordlist struct {
+	Name  string
+	words []string
+	index map[string]int
+	// separator joins the words of a mnemonic, the ideographic space for Japanese
+	separator string
+}
+
+// BIP-39 wordlists
+var (
+	English            = mustLoadWordlist("english", " ")
+	ChineseSimplified  = mustLoadWordlist("chinese_simplified", " ")
+	ChineseTraditional = mustLoadWordlist("chinese_traditional", " ")
+	Czech              = mustLoadWordlist("czech", " ")
+	French             = mustLoadWordlist("french", " ")
+	Italian            = mustLoadWordlist("italian", " ")
+	Japanese           = mustLoadWordlist("japanese", "　")
+	Korean             = mustLoadWordlist("korean", " ")
+	Spanish            = mustLoadWordlist("spanish", " ")
+
+	// Wordlists are all BIP-39 wordlists
+	Wordlists = []*Wordlist{English, ChineseSimplified, ChineseTraditional, Czech, French, Italian, Japanese, Korean, Spanish}
+)
+
+var (
+	errMnemonicWordCount = errors.New("mnemonic must have 12, 15, 18, 21 or 24 words")
+	errMnemonicChecksum  = errors.New("invalid mnemonic checksum")
+)
+
+func mustLoadWordlist(name, separator string) *Wordlist {
+	data, err := wordlistFiles.ReadFile("wordlists/" + name + ".txt")
+	if err != nil {
+		panic(err)
+	}
+	words := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(words) != 2048 {
+		panic(fmt.Sprintf("wordlist %s has %d words", name, len(words)))
+	}
+	index := make(map[string]int, len(words))
+	for i, word := range words {
+		index[norm.NFKD.String(word)] = i
+	}
+	return &Wordlist{Name: name, words: words, index: index, separator: separator}
+}
+
+// GenerateMnemonic generates a random mnemonic with strength bits of entropy, 128 for 12 words up to 256 for 24 words.
+// A nil wordlist is English
+func GenerateMnemonic(wordlist *Wordlist, strength int) (string, error) {
+	if strength < 128 || strength > 256 || strength%32 != 0 {
+		return "", fmt.Errorf("invalid mnemonic strength %d, must be a multiple of 32 between 128 and 256", strength)
+	}
+	entropy := make([]byte, strength/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return EntropyToMnemonic(entropy, wordlist)
+}
+
+// EntropyToMnemonic encodes 16 to 32 bytes of entropy as a mnemonic, a nil wordlist is English
+func EntropyToMnemonic(entropy []byte, wordlist *Wordlist) (string, error) {
+	if wordlist == nil {
+		wordlist = English
+	}
+	if len(entropy) < 16 || len(entropy) > 32 || len(entropy)%4 != 0 {
+		return "", fmt.Errorf("invalid entropy length %d, must be a multiple of 4 between 16 and 32", len(entropy))
+	}
+	checksumBits := len(entropy) / 4
+	hash := sha256.Sum256(entropy)
+	// entropy || checksum as a number, read 11 bits per word from the end
+	bits := new(big.Int).SetBytes(entropy)
+	bits.Lsh(bits, uint(checksumBits))
+	bits.Or(bits, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	words := make([]string, (len(entropy)*8+checksumBits)/11)
+	mask := big.NewInt(2047)
+	for i := len(words) - 1; i >= 0; i-- {
+		words[i] = wordlist.words[new(big.Int).And(bits, mask).Int64()]
+		bits.Rsh(bits, 11)
+	}
+	return strings.Join(words, wordlist.separator), nil
+}
+
+// MnemonicToEntropy decodes a mnemonic back to its entropy and verifies its checksum, a nil wordlist is English
+func MnemonicToEntropy(mnemonic string, wordlist *Wordlist) ([]byte, error) {
+	if wordlist == nil {
+		wordlist = English
+	}
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, errMnemonicWordCount
+	}
+	bits := new(big.Int)
+	for _, word := range words {
+		i, ok := wordlist.index[word]
+		if !ok {
+			return nil, fmt.Errorf("word %q is not in the %s wordlist", word, wordlist.Name)
+		}
+		bits.Lsh(bits, 11)
+		bits.Or(bits, big.NewInt(int64(i)))
+	}
+
+	checksumBits := len(words) / 3
+	checksum := new(big.Int).And(bits, big.NewInt(1<<checksumBits-1))
+	bits.Rsh(bits, uint(checksumBits))
+	entropy := make([]byte, checksumBits*4)
+	bits.FillBytes(entropy)
+	hash := sha256.Sum256(entropy)
+	if checksum.Int64() != int64(hash[0]>>(8-checksumBits)) {
+		return nil, errMnemonicChecksum
+	}
+	return entropy, nil
+}
+
+// ValidateMnemonic checks the words and the checksum of a mnemonic, a nil wordlist is English
+func ValidateMnemonic(mnemonic string, wordlist *Wordlist) error {
+	_, err := MnemonicToEntropy(mnemonic, wordlist)
+	return err
+}
+
+// MnemonicToSeed derives the 64 byte BIP-39 seed of a mnemonic and an optional passphrase.
+// The mnemonic is not validated, see ValidateMnemonic
+func MnemonicToSeed(mnemonic, passphrase string) []byte {
+	password := norm.NFKD.String(strings.Join(strings.Fields(mnemonic), " "))
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+	return pbkdf2.Key([]byte(password), []byte(salt), 2048, 64, sha512.New)
+}
@@ -0,0 +1,63 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestEntropyToMnemonic(t *testing.T) {
+	tests := []struct {
+		entropy  string
+		mnemonic string
+	}{
+		{"00000000000000000000000000000000", "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"},
+		{"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f", "legal winner thank year wave sausage worth useful legal winner thank yellow"},
+		{"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote"},
+	}
+	for _, tt := range tests {
+		entropy, _ := hex.DecodeString(tt.entropy)
+		mnemonic, err := EntropyToMnemonic(entropy, nil)
+		if err != nil || mnemonic != tt.mnemonic {
+			t.Errorf("expected %q, got %q (%v)", tt.mnemonic, mnemonic, err)
+		}
+		decoded, err := MnemonicToEntropy(mnemonic, English)
+		if err != nil || !bytes.Equal(decoded, entropy) {
+			t.Errorf("expected entropy %s, got %x (%v)", tt.entropy, decoded, err)
+		}
+	}
+
+	seed := MnemonicToSeed(tests[0].mnemonic, "TREZOR")
+	if want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"; hex.EncodeToString(seed) != want {
+		t.Errorf("unexpected seed %x", seed)
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	for _, mnemonic := range []string{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon notaword",
+	} {
+		if err := ValidateMnemonic(mnemonic, nil); err == nil {
+			t.Errorf("expected %q to be invalid", mnemonic)
+		}
+	}
+
+	for _, wordlist := range Wordlists {
+		mnemonic, err := GenerateMnemonic(wordlist, 160)
+		if err != nil {
+			t.Fatalf("GenerateMnemonic failed: %v", err)
+		}
+		if err := ValidateMnemonic(mnemonic, wordlist); err != nil {
+			t.Errorf("%s: generated mnemonic %q is invalid: %v", wordlist.Name, mnemonic, err)
+		}
+		if wordlist == Japanese && !strings.Contains(mnemonic, "　") {
+			t.Errorf("expected ideographic spaces in the Japanese mnemonic %q", mnemonic)
+		}
+		if _, err := MnemonicToHDKey(mnemonic, ""); err != nil {
+			t.Errorf("%s: MnemonicToHDKey failed: %v", wordlist.Name, err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package wallet
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"github.com/AutoArbi/go-viem/util"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"strings"
+)
+
+var _ Account = (*PrivateKeyAccount)(nil)
+
+// PrivateKeyAccount is an Account that signs with a private key held in memory
+type PrivateKeyAccount struct {
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+}
+
+// NewPrivateKeyAccount creates an Account for the private key
+func NewPrivateKeyAccount(privateKey *ecdsa.PrivateKey) *PrivateKeyAccount {
+	return &PrivateKeyAccount{
+		privateKey: privateKey,
+		address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+	}
+}
+
+// PrivateKeyAccountFromHex creates an Account for a hex encoded private key, with or without 0x prefix
+func PrivateKeyAccountFromHex(privateKeyHex string) (*PrivateKeyAccount, error) {
+	privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(privateKeyHex, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKeyAccount(privateKey), nil
+}
+
+// Address returns the address of the account
+func (a *PrivateKeyAccount) Address() common.Address {
+	return a.address
+}
+
+// SignTransaction signs tx for the chain
+func (a *PrivateKeyAccount) SignTransaction(ctx context.Context, tx *ethTypes.Transaction, chainID *big.Int) (*ethTypes.Transaction, error) {
+	return ethTypes.SignTx(tx, ethTypes.LatestSignerForChainID(chainID), a.privateKey)
+}
+
+// SignMessage signs an EIP-191 personal message
+func (a *PrivateKeyAccount) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	return a.signHash(accounts.TextHash(message))
+}
+
+// SignTypedData signs EIP-712 typed data given as JSON
+func (a *PrivateKeyAccount) SignTypedData(ctx context.Context, typedDataJSON string) ([]byte, error) {
+	hash, err := util.TypedDataHash(typedDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	return a.signHash(hash.Bytes())
+}
+
+// SignAuthorization signs an EIP-7702 authorization
+func (a *PrivateKeyAccount) SignAuthorization(ctx context.Context, auth ethTypes.SetCodeAuthorization) (ethTypes.SetCodeAuthorization, error) {
+	return ethTypes.SignSetCode(a.privateKey, auth)
+}
+
+// signHash signs a 32 byte hash and moves v to 27 or 28
+func (a *PrivateKeyAccount) signHash(hash []byte) ([]byte, error) {
+	signature, err := crypto.Sign(hash, a.privateKey)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
@@ -0,0 +1,53 @@
+package wallet
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testPrivateKey = "0x4c0883a69102937d6231471b5dbb6204fe512961708279a0d02c0b9a0d3d8a27"
+
+func TestPrivateKeyAccount(t *testing.T) {
+	account, err := PrivateKeyAccountFromHex(testPrivateKey)
+	if err != nil {
+		t.Fatalf("PrivateKeyAccountFromHex failed: %v", err)
+	}
+	ctx := context.Background()
+
+	signature, err := account.SignMessage(ctx, []byte("hello world"))
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	if len(signature) != 65 || signature[64] != 27 && signature[64] != 28 {
+		t.Fatalf("expected 65 byte signature with v 27 or 28, got %x", signature)
+	}
+	signature[64] -= 27
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte("hello world")), signature)
+	if err != nil || crypto.PubkeyToAddress(*pub) != account.Address() {
+		t.Errorf("message signature recovers to %v (%v)", pub, err)
+	}
+
+	to := common.HexToAddress("0x1234")
+	tx := ethTypes.NewTx(&ethTypes.DynamicFeeTx{ChainID: big.NewInt(10), Nonce: 1, Gas: 21000, To: &to, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2)})
+	signed, err := account.SignTransaction(ctx, tx, big.NewInt(10))
+	if err != nil {
+		t.Fatalf("SignTransaction failed: %v", err)
+	}
+	if sender, err := ethTypes.Sender(ethTypes.LatestSignerForChainID(big.NewInt(10)), signed); err != nil || sender != account.Address() {
+		t.Errorf("transaction sender %s (%v)", sender, err)
+	}
+
+	auth, err := account.SignAuthorization(ctx, ethTypes.SetCodeAuthorization{Address: to, Nonce: 2})
+	if err != nil {
+		t.Fatalf("SignAuthorization failed: %v", err)
+	}
+	if authority, err := auth.Authority(); err != nil || authority != account.Address() {
+		t.Errorf("authorization authority %s (%v)", authority, err)
+	}
+}
@@ -1 +1,23 @@
 package wallet
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// Account signs transactions, messages and authorizations for an address. Implementations may keep the key in
+// memory, in an HSM or KMS, or forward the requests to a remote signer
+type Account interface {
+	// Address returns the address of the account
+	Address() common.Address
+	// SignTransaction signs tx for the chain and returns the signed transaction
+	SignTransaction(ctx context.Context, tx *ethTypes.Transaction, chainID *big.Int) (*ethTypes.Transaction, error)
+	// SignMessage signs an EIP-191 personal message and returns the 65 byte signature r || s || v with v 27 or 28
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+	// SignTypedData signs EIP-712 typed data given as JSON and returns the 65 byte signature r || s || v with v 27 or 28
+	SignTypedData(ctx context.Context, typedDataJSON string) ([]byte, error)
+	// SignAuthorization signs an EIP-7702 authorization to delegate the code of the account to auth.Address
+	SignAuthorization(ctx context.Context, auth ethTypes.SetCodeAuthorization) (ethTypes.SetCodeAuthorization, error)
+}
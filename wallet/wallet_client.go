@@ -1,8 +1,19 @@
 package wallet
 
-import "github.com/AutoArbi/go-viem/client"
+import (
+	"context"
+	"encoding/json"
+	"github.com/AutoArbi/go-viem/types"
+)
+
+// Requester is the part of client.Transport used by Client, declared here as wallet cannot import client
+type Requester interface {
+	Request(ctx context.Context, method types.RPCMethod, params ...any) (json.RawMessage, error)
+}
 
 // Client is a Client for public Ethereum RPC methods
+//
+// Deprecated: use client.Client with WithAccount or WithPrivateKey for wallet actions
 type Client struct {
-	Client client.Transport
+	Client Requester
 }